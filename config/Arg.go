@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+)
+
+type (
+	// Arg is a positional arg, like `src` and `dst` in `tool copy src.txt dst.txt`. See WithArg().
+	Arg struct {
+		Name string
+		Desc string
+		// Min and Max are the number of values. Max is ArgUnlimited for a variadic arg.
+		//
+		// default: exactly 1
+		Min int
+		Max int
+		// Validate is called for each value. Optional.
+		Validate func(s string) error
+	}
+
+	// ArgValues are the positional args values found during Init(), by Arg name.
+	ArgValues map[string][]string
+
+	argOptions func(a *Arg) error
+)
+
+// ArgUnlimited as Arg.Max for a variadic arg.
+const ArgUnlimited = -1
+
+// WithArg declares a positional arg, after the subcommands. The order of the calls is the order on the command line.
+//
+// The values are validated during Init() and passed to the command handler in Invocation.Args, see WithRun().
+// Without any arg declared, an extra arg is an error (undefined command, or too many args after `--`).
+func WithArg(name string, opts ...argOptions) configOptionsF {
+	return func(c *Manager) error {
+		a := Arg{Name: name, Min: 1, Max: 1}
+		for _, opt := range opts {
+			if opt == nil {
+				continue
+			}
+			if err := opt(&a); err != nil {
+				return err
+			}
+		}
+		if name == "" {
+			return errors.ConfigError{Err: fmt.Errorf("arg name can't be empty")}
+		}
+		for _, a2 := range c.Args {
+			if a2.Name == name {
+				return errors.ConfigError{Err: errors.ArgError{Name: name, Err: fmt.Errorf("2 args have the same name (id)")}}
+			}
+		}
+		c.Args = append(c.Args, a)
+		return nil
+	}
+}
+
+// WithArgDesc to show in the usage.
+func WithArgDesc(d string) argOptions {
+	return func(a *Arg) error {
+		a.Desc = d
+		return nil
+	}
+}
+
+// WithArgArity is the number of values expected. Use min=max for an exact number, max=ArgUnlimited for a variadic arg.
+//
+// default: exactly 1
+func WithArgArity(min int, max int) argOptions {
+	return func(a *Arg) error {
+		if min < 0 || (max != ArgUnlimited && max < min) {
+			return errors.ConfigError{Err: errors.ArgError{Name: a.Name, Err: fmt.Errorf("invalid arity, min:%d max:%d", min, max)}}
+		}
+		a.Min = min
+		a.Max = max
+		return nil
+	}
+}
+
+// WithArgValidator is called for each value during Init().
+func WithArgValidator(f func(s string) error) argOptions {
+	return func(a *Arg) error {
+		a.Validate = f
+		return nil
+	}
+}
+
+// Get is the first value of the arg, empty when missing.
+func (av ArgValues) Get(name string) string {
+	if len(av[name]) == 0 {
+		return ""
+	}
+	return av[name][0]
+}
+
+// parseArgs matches the values with the declared args, left to right.
+//
+// An arg takes as many values as possible, keeping enough for the minimum of the next args.
+func (c *Manager) parseArgs(in []string) (ArgValues, error) {
+	res := ArgValues{}
+	minRemaining := 0
+	for _, a := range c.Args {
+		if len(in)-minRemaining < a.Min {
+			return nil, errors.ArgError{Name: a.Name, Err: fmt.Errorf("missing value, expect %s", a.arity())}
+		}
+		minRemaining += a.Min
+	}
+	i := 0
+	for _, a := range c.Args {
+		minRemaining -= a.Min
+		n := len(in) - i - minRemaining
+		if a.Max != ArgUnlimited && n > a.Max {
+			n = a.Max
+		}
+		for _, v := range in[i : i+n] {
+			if a.Validate == nil {
+				continue
+			}
+			if err := a.Validate(v); err != nil {
+				return nil, errors.ArgError{Name: a.Name, Err: fmt.Errorf("invalid value:%q, %w", v, err)}
+			}
+		}
+		res[a.Name] = append([]string{}, in[i:i+n]...)
+		i += n
+	}
+	if i < len(in) {
+		return nil, errors.ArgError{Err: fmt.Errorf("too many args:%q", in[i:])}
+	}
+	return res, nil
+}
+
+// argsUsage is the command line form, like `<src> <dst> [files...]`
+func (c Manager) argsUsage() string {
+	res := []string{}
+	for _, a := range c.Args {
+		switch {
+		case a.Min == 0 && a.Max == 1:
+			res = append(res, "["+a.Name+"]")
+		case a.Min == 0:
+			res = append(res, "["+a.Name+"...]")
+		case a.Min == 1 && a.Max == 1:
+			res = append(res, "<"+a.Name+">")
+		default:
+			res = append(res, "<"+a.Name+">...")
+		}
+	}
+	return strings.Join(res, " ")
+}
+
+// arity like `1 value` or `at least 1 value`
+func (a Arg) arity() string {
+	plural := func(n int) string {
+		if n > 1 {
+			return fmt.Sprintf("%d values", n)
+		}
+		return fmt.Sprintf("%d value", n)
+	}
+	switch {
+	case a.Max == ArgUnlimited:
+		return "at least " + plural(a.Min)
+	case a.Min == a.Max:
+		return plural(a.Min)
+	}
+	return fmt.Sprintf("%d to %s", a.Min, plural(a.Max))
+}
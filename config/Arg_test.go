@@ -0,0 +1,159 @@
+package config
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param"
+)
+
+func TestManager_parseArgs(t *testing.T) {
+	notEmpty := func(s string) error {
+		if s == "" {
+			return fmt.Errorf("empty")
+		}
+		return nil
+	}
+	tests := []struct {
+		name    string
+		opts    []configOptionsF
+		in      []string
+		want    ArgValues
+		wantErr bool
+	}{
+		{
+			name: "no arg declared",
+			in:   []string{},
+			want: ArgValues{},
+		},
+		{
+			name:    "no arg declared, too many",
+			in:      []string{"a"},
+			wantErr: true,
+		},
+		{
+			name: "exact",
+			opts: []configOptionsF{WithArg("src"), WithArg("dst")},
+			in:   []string{"a", "b"},
+			want: ArgValues{"src": {"a"}, "dst": {"b"}},
+		},
+		{
+			name:    "exact missing",
+			opts:    []configOptionsF{WithArg("src"), WithArg("dst")},
+			in:      []string{"a"},
+			wantErr: true,
+		},
+		{
+			name:    "too many",
+			opts:    []configOptionsF{WithArg("src")},
+			in:      []string{"a", "b"},
+			wantErr: true,
+		},
+		{
+			name: "variadic first keeps the minimum for the next",
+			opts: []configOptionsF{WithArg("src", WithArgArity(1, ArgUnlimited)), WithArg("dst")},
+			in:   []string{"a", "b", "c"},
+			want: ArgValues{"src": {"a", "b"}, "dst": {"c"}},
+		},
+		{
+			name: "optional missing",
+			opts: []configOptionsF{WithArg("src"), WithArg("dst", WithArgArity(0, 1))},
+			in:   []string{"a"},
+			want: ArgValues{"src": {"a"}, "dst": {}},
+		},
+		{
+			name: "min max",
+			opts: []configOptionsF{WithArg("files", WithArgArity(1, 2)), WithArg("rest", WithArgArity(0, ArgUnlimited))},
+			in:   []string{"a", "b", "c"},
+			want: ArgValues{"files": {"a", "b"}, "rest": {"c"}},
+		},
+		{
+			name:    "validation",
+			opts:    []configOptionsF{WithArg("src", WithArgValidator(notEmpty))},
+			in:      []string{""},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := c.parseArgs(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Manager.parseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if fmt.Sprintf("%v", got) != fmt.Sprintf("%v", tt.want) {
+				t.Errorf("Manager.parseArgs()\ngot =%v\nwant=%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_Init_args(t *testing.T) {
+	var force bool
+	var got ArgValues
+	pForce, err := param.NewBool("force", func(b bool) error { force = b; return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmdCopy, err := New(
+		WithParams(pForce),
+		WithArg("src", WithArgDesc("file to copy")),
+		WithArg("dst"),
+		WithRun(func(ctx context.Context, inv Invocation) error { got = inv.Args; return nil }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithSubCommand("copy", cmdCopy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{"copy", "-force", "src.txt", "dst.txt"})); err != nil {
+		t.Fatal(err)
+	}
+	if !force || got.Get("src") != "src.txt" || got.Get("dst") != "dst.txt" {
+		t.Errorf("Init() got force:%t args:%v", force, got)
+	}
+
+	err = c.Init(context.Background(), WithInputArgs([]string{"copy", "src.txt"}))
+	if err == nil {
+		t.Fatal("Init() expect error for missing arg")
+	}
+	argErr := errors.ArgError{}
+	if !stderrors.As(err, &argErr) || argErr.Name != "dst" {
+		t.Errorf("Init() expect ArgError for dst, got =%s", err)
+	}
+}
+
+func TestManager_Init_argsNotDeclared(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []configOptionsF
+		wantErr bool
+	}{
+		{name: "extra arg", wantErr: true},
+		{name: "ignore commands", opts: []configOptionsF{WithIgnoreCommands(true)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(append(tt.opts, WithFlagStyle(FlagStyleGNU))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.Init(context.Background(), WithInputArgs([]string{"--", "extra"}))
+			argErr := errors.ArgError{}
+			if tt.wantErr && !stderrors.As(err, &argErr) || !tt.wantErr && err != nil {
+				t.Errorf("Init() with `-- extra`\ngot =%v\nwant ArgError=%t", err, tt.wantErr)
+			}
+		})
+	}
+}
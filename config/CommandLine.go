@@ -0,0 +1,187 @@
+package config
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"log/slog"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param/paramname"
+	"github.com/vincentkerdraon/configo/config/param/source"
+	"github.com/vincentkerdraon/configo/config/subcommand"
+)
+
+type (
+	// commandLine is the command line, split by subcommand level.
+	commandLine struct {
+		// subCommands selected, without level 0.
+		subCommands []subcommand.SubCommand
+		// managers by level, starting with the root Manager.
+		managers []*Manager
+		// flags by param. The flag value is set when found in the command line.
+		flags map[flagKey]*flagValue
+		// args are the positional args.
+		args []string
+		// argsUnknown when the parsing stopped at an ignored unknown flag, see IgnoreFlagProvidedNotDefined.
+		argsUnknown bool
+		// help when the usage is asked, with the built-in help flag or command.
+		help bool
+	}
+
+	// flagKey identifies a param, with the subcommand level where it is declared. (0 for the root Manager)
+	flagKey struct {
+		depth int
+		name  paramname.ParamName
+	}
+)
+
+// parseCommandLine reads the subcommands, the flags and the positional args.
+//
+// A flag is accepted at the level where it appears: the params of this subcommand and the params inherited from the parents.
+// The parent flags can then be written before the subcommand name, like `tool -v deploy -region=x prod`.
+//
+// The usage is asked with the built-in help flag anywhere, or the help command first, like `tool help deploy`.
+func (c *Manager) parseCommandLine(ctx context.Context, args []string) (commandLine, error) {
+	res := commandLine{subCommands: []subcommand.SubCommand{}, flags: map[flagKey]*flagValue{}}
+	levels := []*Manager{c}
+	priorities := [][]source.Source{source.PriorityDefault()}
+	if c.SourcePriority != nil {
+		priorities[0] = c.SourcePriority
+	}
+	namings := []naming{c.naming(nil)}
+	path := func() []subcommand.SubCommand {
+		return append([]subcommand.SubCommand{subCommandLevel0}, res.subCommands...)
+	}
+
+	rest := args
+	for {
+		m := levels[len(levels)-1]
+		defs := withHelpFlag(commandLineFlags(levels, priorities, namings, res.flags), res.flags)
+		remaining, terminated, err := parseFlags(c.FlagStyle, defs, rest, false)
+		if err == nil && !terminated && len(remaining) > 0 {
+			if c.IgnoreCommands && len(m.Args) == 0 {
+				rest = remaining[1:]
+				continue
+			}
+			if name, sub, ok := m.lookupSubCommand(subcommand.SubCommand(remaining[0])); ok && !c.IgnoreCommands {
+				if sub.Deprecated != "" {
+					c.Logger.WarnContext(ctx, "deprecated command", slog.String("command", remaining[0]), slog.String("replacement", sub.Deprecated))
+				}
+				levels = append(levels, sub)
+				//SubCommands inherit the priority, unless redefined.
+				priority := priorities[len(priorities)-1]
+				if sub.SourcePriority != nil {
+					priority = sub.SourcePriority
+				}
+				priorities = append(priorities, priority)
+				res.subCommands = append(res.subCommands, name)
+				namings = append(namings, c.naming(res.subCommands))
+				rest = remaining[1:]
+				continue
+			}
+			if len(levels) == 1 && !res.help && m.isHelpCommand(remaining[0]) {
+				res.help = true
+				rest = remaining[1:]
+				continue
+			}
+			if len(m.Args) == 0 {
+				return res, errors.ConfigError{
+					SubCommands: append(path(), subcommand.SubCommand(remaining[0])),
+					Err: errors.SubCommandUnknownError{
+						Name:        remaining[0],
+						Declared:    m.visibleSubCommands(),
+						Suggestions: m.suggestSubCommands(remaining[0]),
+					}}
+			}
+			//The positional args start. With FlagStyleGNU, the flags can still be mixed with them.
+			if c.FlagStyle == FlagStyleGNU {
+				remaining, _, err = parseFlags(c.FlagStyle, defs, remaining, true)
+			}
+		}
+		if err != nil {
+			if stderrors.As(err, &errors.ConfigError{}) {
+				return res, err
+			}
+			c.Logger.WarnContext(ctx, "fail parse flags", slog.String("err", err.Error()), slog.Bool("IgnoreFlagProvidedNotDefined", c.IgnoreFlagProvidedNotDefined))
+			if !(c.IgnoreFlagProvidedNotDefined && strings.HasPrefix(err.Error(), errFlagProvidedNotDefined)) {
+				return res, errors.ConfigError{SubCommands: path(), Err: errors.FlagUnknownError{Err: err, Suggestions: suggestFlags(c.FlagStyle, defs, err)}}
+			}
+			res.argsUnknown = true
+		}
+		res.args = remaining
+		break
+	}
+	res.managers = levels
+	res.help = res.help || isHelpFlag(res.flags)
+	return res, checkLocalFlags(levels, res)
+}
+
+// usageModel is the usage of the last subcommand, for the help.
+func (cl commandLine) usageModel(root *Manager) UsageCommand {
+	var priority []source.Source
+	for _, m := range cl.managers[:len(cl.managers)-1] {
+		if m.SourcePriority != nil {
+			priority = m.SourcePriority
+		}
+	}
+	return cl.managers[len(cl.managers)-1].usageModel(root, cl.subCommands, priority)
+}
+
+// commandLineFlags are the flags accepted at the deepest level, including the params of the parents.
+//
+// The local params of the parents are included, to give a better error than an unknown flag. See checkLocalFlags().
+func commandLineFlags(levels []*Manager, priorities [][]source.Source, namings []naming, flags map[flagKey]*flagValue) []*flagDef {
+	res := []*flagDef{}
+	seen := map[string]bool{}
+	for depth := len(levels) - 1; depth >= 0; depth-- {
+		for _, p := range levels[depth].sortedParams() {
+			pi := paramImpl{Param: p, priority: priorities[depth], naming: namings[depth]}
+			if p.SourcePriority != nil {
+				pi.priority = p.SourcePriority
+			}
+			if !p.Flag.Use || !pi.reads(source.Flag) {
+				continue
+			}
+			short := ""
+			if p.Flag.ShortName != 0 {
+				short = "-" + string(p.Flag.ShortName)
+			}
+			if p.IsSubCommandLocal && depth < len(levels)-1 && (seen[pi.flagName()] || seen[short]) {
+				continue
+			}
+			seen[pi.flagName()] = true
+			if short != "" {
+				seen[short] = true
+			}
+			key := flagKey{depth: depth, name: p.Name}
+			if flags[key] == nil {
+				flags[key] = &flagValue{isBool: pi.isBool(), sep: p.ListSeparator()}
+			}
+			def := &flagDef{name: pi.flagName(), short: p.Flag.ShortName, value: flags[key]}
+			if p.EnvVar.Use {
+				def.envVar = pi.envVarName()
+			}
+			res = append(res, def)
+		}
+	}
+	return res
+}
+
+// checkLocalFlags fails when the flag of a local param is used, but not with the last subcommand. Like `tool -localParam subcommand`.
+func checkLocalFlags(levels []*Manager, cl commandLine) error {
+	for depth, m := range levels[:len(levels)-1] {
+		for _, p := range m.sortedParams() {
+			if fv := cl.flags[flagKey{depth: depth, name: p.Name}]; p.IsSubCommandLocal && fv != nil && fv.isSet {
+				return errors.ParamConfigError{
+					SubCommands: append([]subcommand.SubCommand{subCommandLevel0}, cl.subCommands[:depth]...),
+					ParamName:   p.Name,
+					Err:         fmt.Errorf("local param, not available with the subcommand:%q", cl.subCommands[depth]),
+				}
+			}
+		}
+	}
+	return nil
+}
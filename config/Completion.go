@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/source"
+	"github.com/vincentkerdraon/configo/config/subcommand"
+)
+
+type (
+	// Shell for the completion script
+	Shell string
+)
+
+const (
+	ShellBash Shell = "bash"
+	ShellZsh  Shell = "zsh"
+	ShellFish Shell = "fish"
+)
+
+// completeCommand is the hidden first argument used by the completion scripts.
+// `program __complete sub1 -fl` prints the candidates for `-fl`, one per line.
+const completeCommand = "__complete"
+
+// CompletionScript generates the completion script for a shell.
+//
+// The script calls `program __complete ...` (handled by Init()), so the completion follows the current Manager definition.
+// example: `source <(program completion bash)` with a subcommand printing this script.
+func (c *Manager) CompletionScript(shell Shell, program string) (string, error) {
+	if program == "" {
+		return "", errors.ConfigError{Err: fmt.Errorf("mandatory program name")}
+	}
+	funcName := "_configo_" + regexp.MustCompile(`[^a-zA-Z0-9_]`).ReplaceAllString(program, "_")
+	switch shell {
+	case ShellBash:
+		return fmt.Sprintf(`# bash completion for %[1]s
+%[2]s() {
+	local line="${COMP_LINE:0:COMP_POINT}"
+	local -a words
+	read -r -a words <<< "$line"
+	[[ "$line" == *" " ]] && words+=("")
+	local IFS=$'\n'
+	COMPREPLY=($(%[1]q %[3]s "${words[@]:1}" 2>/dev/null))
+	# "=" splits the words in bash, only keep the value part.
+	local last="${words[${#words[@]}-1]}"
+	if [[ "$last" == *=* && "$COMP_WORDBREAKS" == *=* ]]; then
+		COMPREPLY=("${COMPREPLY[@]#*=}")
+	fi
+}
+complete -o default -F %[2]s %[1]s
+`, program, funcName, completeCommand), nil
+	case ShellZsh:
+		return fmt.Sprintf(`#compdef %[1]s
+# zsh completion for %[1]s
+%[2]s() {
+	local -a completions
+	completions=("${(@f)$(%[1]q %[3]s "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+	compadd -- "${completions[@]}"
+}
+compdef %[2]s %[1]s
+`, program, funcName, completeCommand), nil
+	case ShellFish:
+		return fmt.Sprintf(`# fish completion for %[1]s
+function %[2]s
+	set -l args (commandline -opc)[2..-1] (commandline -ct)
+	%[1]s %[3]s $args 2>/dev/null
+end
+complete -c %[1]s -f -a '(%[2]s)'
+`, program, funcName, completeCommand), nil
+	}
+	return "", errors.ConfigError{Err: fmt.Errorf("unknown shell:%q, expect one of:%v", shell, []Shell{ShellBash, ShellZsh, ShellFish})}
+}
+
+// complete finds the candidates for the last arg. The other args are already typed.
+func (c *Manager) complete(args []string) []string {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	current := args[len(args)-1]
+	levels := []*Manager{c}
+	var flagWaitingValue *param.Param
+	for _, arg := range args[:len(args)-1] {
+		m := levels[len(levels)-1]
+		if flagWaitingValue != nil {
+			flagWaitingValue = nil
+			continue
+		}
+		if strings.HasPrefix(arg, "-") {
+			if !strings.Contains(arg, "=") {
+				flagWaitingValue = completeFindFlag(levels, c.naming(nil), strings.TrimLeft(arg, "-"))
+				if flagWaitingValue != nil && (paramImpl{Param: *flagWaitingValue}).isBool() {
+					//a switch does not take the next arg as value
+					flagWaitingValue = nil
+				}
+			}
+			continue
+		}
+		if _, sub, ok := m.lookupSubCommand(subcommand.SubCommand(arg)); ok {
+			levels = append(levels, sub)
+		}
+	}
+
+	res := []string{}
+	switch {
+	case flagWaitingValue != nil:
+		res = completeEnumValues(*flagWaitingValue, "", current)
+	case strings.HasPrefix(current, "-") && strings.Contains(current, "="):
+		i := strings.Index(current, "=")
+		if p := completeFindFlag(levels, c.naming(nil), strings.TrimLeft(current[:i], "-")); p != nil {
+			res = completeEnumValues(*p, current[:i+1], current[i+1:])
+		}
+	case strings.HasPrefix(current, "-"):
+		dashes := "-"
+		if strings.HasPrefix(current, "--") || c.FlagStyle == FlagStyleGNU {
+			dashes = "--"
+		}
+		for _, p := range completeVisibleParams(levels) {
+			if p.IsHidden {
+				continue
+			}
+			name := dashes + paramImpl{Param: p, naming: c.naming(nil)}.flagName()
+			if strings.HasPrefix(name, current) {
+				res = append(res, name)
+			}
+		}
+	default:
+		for subCmd, sub := range levels[len(levels)-1].SubCommands {
+			if !sub.Hidden && strings.HasPrefix(subCmd.String(), current) {
+				res = append(res, subCmd.String())
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// completeVisibleParams are the params with a flag for the current subcommand, including the inherited params.
+//
+// The priority is inherited from the parents, like in Init().
+func completeVisibleParams(levels []*Manager) []param.Param {
+	res := []param.Param{}
+	var priority []source.Source
+	for i, m := range levels {
+		priority = m.inheritPriority(priority)
+		for _, p := range m.Params {
+			if p.IsSubCommandLocal && i < len(levels)-1 {
+				continue
+			}
+			pi := paramImpl{Param: p, priority: paramPriority(p, priority)}
+			if !p.Flag.Use || !pi.reads(source.Flag) {
+				continue
+			}
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+func completeFindFlag(levels []*Manager, n naming, name string) *param.Param {
+	for _, p := range completeVisibleParams(levels) {
+		if (paramImpl{Param: p, naming: n}).flagName() == name || (p.Flag.ShortName != 0 && string(p.Flag.ShortName) == name) {
+			return &p
+		}
+	}
+	return nil
+}
+
+func completeEnumValues(p param.Param, prefix string, current string) []string {
+	res := []string{}
+	values := p.EnumValues
+	if len(values) == 0 && (paramImpl{Param: p}).isBool() {
+		values = []string{"true", "false"}
+	}
+	for _, v := range values {
+		if strings.HasPrefix(v, current) || p.EnumCaseInsensitive && strings.HasPrefix(strings.ToLower(v), strings.ToLower(current)) {
+			res = append(res, prefix+v)
+		}
+	}
+	return res
+}
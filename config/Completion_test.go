@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/source"
+)
+
+func TestManager_complete(t *testing.T) {
+	noop := func(s string) error { return nil }
+	pVerbose, _ := param.New("verbose", noop)
+	pLocal, _ := param.New("local", noop, param.WithIsSubCommandLocal(true))
+	pNoFlag, _ := param.New("noFlag", noop, param.WithFlag(param.WithReadFlag(false)))
+	pRegion, _ := param.New("region", noop, param.WithEnumValues("ca-central-1", "us-east-1", "eu-west-1"))
+	cDeploy, _ := New(WithParams(pRegion))
+	cDelete, _ := New()
+	c, _ := New(WithParams(pVerbose, pLocal, pNoFlag), WithSubCommand("deploy", cDeploy), WithSubCommand("delete", cDelete))
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{args: []string{}, want: []string{"delete", "deploy"}},
+		{args: []string{"dep"}, want: []string{"deploy"}},
+		{args: []string{"-"}, want: []string{"-local", "-verbose"}},
+		{args: []string{"--v"}, want: []string{"--verbose"}},
+		{args: []string{"deploy", "-"}, want: []string{"-region", "-verbose"}},
+		{args: []string{"deploy", "-region="}, want: []string{"-region=ca-central-1", "-region=eu-west-1", "-region=us-east-1"}},
+		{args: []string{"deploy", "-region=u"}, want: []string{"-region=us-east-1"}},
+		{args: []string{"deploy", "-region", "e"}, want: []string{"eu-west-1"}},
+		{args: []string{"deploy", "-verbose=1", ""}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if got := c.complete(tt.args); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Manager.complete()\ngot =%v\nwant=%v", got, tt.want)
+			}
+		})
+	}
+
+	var out bytes.Buffer
+	exitCode := -1
+	err := c.Init(context.Background(),
+		WithInputArgs([]string{completeCommand, "deploy", "-reg"}),
+		WithOutput(&out),
+		WithExit(func(code int) { exitCode = code }),
+	)
+	if err != nil || exitCode != 0 || out.String() != "-region\n" {
+		t.Errorf("Init() with %s\ngot =%q (exit %d, err %v)\nwant=%q", completeCommand, out.String(), exitCode, err, "-region\n")
+	}
+}
+
+func TestManager_complete_inheritedPriority(t *testing.T) {
+	noop := func(s string) error { return nil }
+	pVerbose, _ := param.New("verbose", noop)
+	pZone, _ := param.New("zone", noop)
+	pRegion, _ := param.New("region", noop, param.WithSourcePriority(source.Flag, source.Default))
+	cDeploy, _ := New(WithParams(pZone, pRegion))
+	c, _ := New(WithParams(pVerbose), WithSubCommand("deploy", cDeploy), WithSourcePriority(source.EnvVar, source.Default))
+
+	want := []string{"-region"}
+	if got := c.complete([]string{"deploy", "-"}); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Manager.complete()\ngot =%v\nwant=%v", got, want)
+	}
+}
+
+func TestManager_CompletionScript(t *testing.T) {
+	c, _ := New()
+	for _, shell := range []Shell{ShellBash, ShellZsh, ShellFish} {
+		script, err := c.CompletionScript(shell, "my-tool")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(script, "_configo_my_tool") || !strings.Contains(script, completeCommand) {
+			t.Errorf("CompletionScript(%s)\ngot =%s", shell, script)
+		}
+	}
+	if _, err := c.CompletionScript("powershell", "my-tool"); err == nil {
+		t.Errorf("CompletionScript(powershell) expect error")
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/vincentkerdraon/configo/config/errors"
 	"github.com/vincentkerdraon/configo/config/param"
 	"github.com/vincentkerdraon/configo/config/param/paramname"
+	"github.com/vincentkerdraon/configo/config/param/source"
 	"github.com/vincentkerdraon/configo/config/subcommand"
 	"github.com/vincentkerdraon/configo/lock"
 )
@@ -34,12 +36,87 @@ type (
 
 		SubCommands map[subcommand.SubCommand]*Manager
 
+		//Aliases are other names for this Manager when used as a subcommand, see WithAliases().
+		Aliases []subcommand.SubCommand
+
+		//Hidden subcommand, working but not shown in the usage and completion.
+		Hidden bool
+
+		//Deprecated subcommand when not empty. It still runs, with a warning pointing to this replacement.
+		Deprecated string
+
 		Callback func() error
 
+		//Args are the positional args after the subcommands, see WithArg().
+		Args []Arg
+
+		//Run is the command handler, see WithRun().
+		Run func(ctx context.Context, inv Invocation) error
+
+		//PersistentPreRun and PersistentPostRun are called around the Run of this Manager and its SubCommands.
+		PersistentPreRun  func(ctx context.Context, inv Invocation) error
+		PersistentPostRun func(ctx context.Context, inv Invocation) error
+
+		//AtLeastOneOf and ExactlyOneOf are groups of params, see WithAtLeastOneOf().
+		AtLeastOneOf [][]paramname.ParamName
+		ExactlyOneOf [][]paramname.ParamName
+
+		//Validate is called after every param is set, see WithValidate().
+		Validate func(s Snapshot) error
+
 		Logger *slog.Logger
 
+		//ConfigFiles are read once during Init(), see WithConfigFile()
+		ConfigFiles []ConfigFile
+
+		//DotEnvFiles are read once during Init(), see WithDotEnvFiles()
+		DotEnvFiles []string
+
+		//SourcePriority is the reading order for the params of this Manager and its SubCommands, highest priority first.
+		//
+		// default: source.PriorityDefault()
+		SourcePriority []source.Source
+
+		//EnvVarCheckPrefix to warn about the env vars with this prefix, but not matching any param. See WithEnvVarCheck().
+		EnvVarCheckPrefix string
+
+		//EnvPrefix is added in front of the env var names, see WithEnvPrefix(). Only read on the root Manager.
+		EnvPrefix string
+
+		//EnvVarNaming and FlagNaming derive the names from the param names, see WithEnvVarNaming(). Only read on the root Manager.
+		//
+		// default: nil, the param name
+		EnvVarNaming Naming
+		FlagNaming   Naming
+
+		//FlagStyle is how the command line flags are parsed, see WithFlagStyle(). Only read on the root Manager.
+		//
+		// default: FlagStyleGo
+		FlagStyle FlagStyle
+
+		//UsageWidth is the line width of the usage, see WithUsageWidth().
+		//
+		// default: 0, the terminal width
+		UsageWidth int
+
+		//UsageRenderer writes the built-in help, see WithUsageRenderer().
+		//
+		// default: TextRenderer
+		UsageRenderer UsageRenderer
+
 		//lock prevents race condition, mostly when using sync()
 		lock lock.Locker
+
+		//paramsOrder and subCommandsOrder keep the declaration order, for the usage. (Params and SubCommands are maps)
+		paramsOrder      []paramname.ParamName
+		subCommandsOrder []subcommand.SubCommand
+
+		//paramsImpl is the state after Init(). Used by Reload().
+		paramsImpl map[paramname.ParamName]*paramImpl
+		//subCommandsInit are the subcommands selected during Init(), starting with level 0.
+		subCommandsInit []subcommand.SubCommand
+		//managersInit are the Managers selected during Init(), starting with the root. For the rules in Reload().
+		managersInit []*Manager
 	}
 
 	configOptionsF func(r *Manager) error
@@ -89,14 +166,71 @@ func WithSubCommand(subCommand subcommand.SubCommand, config *Manager) configOpt
 		if len(subCommand) == 0 {
 			return errors.ConfigError{Err: fmt.Errorf("subcommands name can't be empty")}
 		}
-		if _, f := c.SubCommands[subCommand]; f {
+		if _, _, f := c.lookupSubCommand(subCommand); f {
 			return errors.ConfigError{Err: fmt.Errorf("2 subcommands have the same name (id)")}
 		}
+		for _, alias := range config.Aliases {
+			if _, _, f := c.lookupSubCommand(alias); f || alias == subCommand {
+				return errors.ConfigError{Err: fmt.Errorf("subcommand alias:%q already used", alias)}
+			}
+		}
 		c.SubCommands[subCommand] = config
+		c.subCommandsOrder = append(c.subCommandsOrder, subCommand)
+		return nil
+	}
+}
+
+// WithAliases are other names for this Manager when used as a subcommand. Like `rm` for `remove`.
+func WithAliases(aliases ...subcommand.SubCommand) configOptionsF {
+	return func(c *Manager) error {
+		for _, alias := range aliases {
+			if len(alias) == 0 {
+				return errors.ConfigError{Err: fmt.Errorf("subcommands alias can't be empty")}
+			}
+		}
+		c.Aliases = append(c.Aliases, aliases...)
 		return nil
 	}
 }
 
+// WithHidden for a subcommand working but not shown in the usage, the documentation and the completion.
+//
+// default:false
+func WithHidden(t bool) configOptionsF {
+	return func(c *Manager) error {
+		c.Hidden = t
+		return nil
+	}
+}
+
+// WithDeprecated for a subcommand still working, but logging a warning (see WithLogger()) pointing to the replacement.
+//
+// Like WithDeprecated(`use "remove"`)
+func WithDeprecated(replacement string) configOptionsF {
+	return func(c *Manager) error {
+		if replacement == "" {
+			return errors.ConfigError{Err: fmt.Errorf("mandatory replacement for a deprecated subcommand")}
+		}
+		c.Deprecated = replacement
+		return nil
+	}
+}
+
+// lookupSubCommand finds a subcommand by name or by alias. Returns the name.
+func (c *Manager) lookupSubCommand(name subcommand.SubCommand) (subcommand.SubCommand, *Manager, bool) {
+	if sub, ok := c.SubCommands[name]; ok {
+		return name, sub, true
+	}
+	for subName, sub := range c.SubCommands {
+		for _, alias := range sub.Aliases {
+			if alias == name {
+				return subName, sub, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
 // WithDescription to show in the usage
 func WithDescription(d string) configOptionsF {
 	return func(c *Manager) error {
@@ -131,7 +265,8 @@ func WithIgnoreCommands(t bool) configOptionsF {
 //
 // Can be called multiple times.
 // This uses default options for all params.
-// This ignores the struct fields or embedded struct. (Needs to be explicit.)
+// The nested structs are read recursively: the field `DB.Host` is the flag `-db-host` and the env var `DB_HOST`.
+// The embedded structs are flattened, unless ignored with the tag `prefix:"-"`. See param.IterateStructFields().
 func WithParamsFromStructTag(in interface{}, prefix string) configOptionsF {
 	return func(c *Manager) error {
 		params, err := param.ParamsFromStructTag(in, prefix)
@@ -160,6 +295,7 @@ func WithParams(params ...*param.Param) configOptionsF {
 				return errors.ParamConfigError{ParamName: p.Name, Err: fmt.Errorf("2 params have the same name (id)")}
 			}
 			c.Params[p.Name] = *p
+			c.paramsOrder = append(c.paramsOrder, p.Name)
 		}
 		return nil
 	}
@@ -177,7 +313,7 @@ func WithLoadErrorHandler(f func(_ paramname.ParamName, consecutiveErrNb int, _
 
 // WithCallback to trigger this function when the parsing is done.
 //
-// Handy for sub commands.
+// Handy for sub commands. See also WithRun() to get the context, the positional args and the values.
 func WithCallback(f func() error) configOptionsF {
 	return func(c *Manager) error {
 		c.Callback = f
@@ -192,3 +328,34 @@ func WithLogger(l *slog.Logger) configOptionsF {
 		return nil
 	}
 }
+
+// WithSourcePriority defines which sources are read and in which order, highest priority first.
+// Sources not listed are not read.
+//
+// Applies to the params of this Manager and its SubCommands. A param can override with param.WithSourcePriority().
+// default: source.PriorityDefault() (Flag > EnvVar > DotEnv > Loader > ConfigFile > Default)
+func WithSourcePriority(priority ...source.Source) configOptionsF {
+	return func(c *Manager) error {
+		if err := source.CheckPriority(priority); err != nil {
+			return errors.ConfigError{Err: err}
+		}
+		c.SourcePriority = priority
+		return nil
+	}
+}
+
+// inheritPriority is the priority of this Manager, or the one inherited from the parents when not redefined.
+func (c *Manager) inheritPriority(parent []source.Source) []source.Source {
+	if c.SourcePriority != nil {
+		return c.SourcePriority
+	}
+	return parent
+}
+
+// paramPriority is the priority of the param when redefined, or the one of its Manager.
+func paramPriority(p param.Param, priority []source.Source) []source.Source {
+	if p.SourcePriority != nil {
+		return p.SourcePriority
+	}
+	return priority
+}
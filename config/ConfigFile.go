@@ -0,0 +1,176 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vincentkerdraon/configo/config/configfile"
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/source"
+	"github.com/vincentkerdraon/configo/config/subcommand"
+)
+
+type (
+	// ConfigFile is a file read once during Init(), see WithConfigFile().
+	ConfigFile struct {
+		Path   string
+		Format configfile.Format
+	}
+
+	// initInputs are the values read once during Init(), shared by all the levels of subcommands.
+	initInputs struct {
+		//configFile keys are lower case.
+		configFile map[string]string
+		//dotEnv are the env vars read in the .env files.
+		dotEnv map[string]string
+		//flags found in the command line, see Manager.parseCommandLine()
+		flags map[flagKey]*flagValue
+	}
+)
+
+// WithConfigFile reads a config file once during Init(). The keys are matched with the param names (case insensitive).
+//
+// Can be called multiple times, the last file overrides the previous ones.
+// A subcommand param can be prefixed with the subcommands, like `deploy.Region` or in a `[deploy]` INI section.
+// Keys not matching any param are an error.
+// Priority: between Default and Loader, see source.PriorityDefault().
+// Formats: configfile.JSON, configfile.INI or any format added with configfile.Register().
+func WithConfigFile(path string, format configfile.Format) configOptionsF {
+	return func(c *Manager) error {
+		if path == "" {
+			return errors.ConfigError{Err: fmt.Errorf("mandatory config file path")}
+		}
+		c.ConfigFiles = append(c.ConfigFiles, ConfigFile{Path: path, Format: format})
+		return nil
+	}
+}
+
+// readConfigFiles reads all the files. Keys are lower case.
+//
+// An array is joined with the separator of the param. The keys of an object are the entries of a map param, like `labels.env` for `-labels=env=prod`.
+func (c *Manager) readConfigFiles() (map[string]string, error) {
+	if len(c.ConfigFiles) == 0 {
+		return nil, nil
+	}
+	known := map[string]param.Param{}
+	c.configFileKeys(nil, known)
+
+	res := map[string]string{}
+	for _, f := range c.ConfigFiles {
+		values, err := configfile.Read(f.Path, f.Format)
+		if err != nil {
+			return nil, errors.ConfigError{Err: errors.ConfigFileError{Path: f.Path, Err: err}}
+		}
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		unknown := []string{}
+		entries := map[string][]string{}
+		for _, k := range keys {
+			lower := strings.ToLower(k)
+			p, ok := known[lower]
+			if !ok {
+				mapKey, entry, found := configFileMapEntry(known, k)
+				if !found {
+					unknown = append(unknown, lower)
+					continue
+				}
+				v, err := configFileValue(known[mapKey], k, values[k])
+				if err != nil {
+					return nil, errors.ConfigError{Err: errors.ConfigFileError{Path: f.Path, Err: err}}
+				}
+				entries[mapKey] = append(entries[mapKey], entry+"="+v)
+				continue
+			}
+			v, err := configFileValue(p, k, values[k])
+			if err != nil {
+				return nil, errors.ConfigError{Err: errors.ConfigFileError{Path: f.Path, Err: err}}
+			}
+			res[lower] = v
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return nil, errors.ConfigError{Err: errors.ConfigFileError{Path: f.Path, Err: fmt.Errorf("%w: %v", errors.ErrConfigFileUnknownKey, unknown)}}
+		}
+		for mapKey, items := range entries {
+			res[mapKey] = strings.Join(items, known[mapKey].ListSeparator())
+		}
+	}
+	return res, nil
+}
+
+// configFileValue is the raw value of the param. The items of an array must not contain the separator.
+func configFileValue(p param.Param, key string, v configfile.Value) (string, error) {
+	sep := p.ListSeparator()
+	if !v.IsList {
+		return v.Value, nil
+	}
+	if sep == "" {
+		return "", fmt.Errorf("key:%q, got an array, param:%q is not a slice or a map", key, p.Name)
+	}
+	for _, item := range v.List {
+		if strings.Contains(item, sep) {
+			return "", fmt.Errorf("key:%q, item:%q contains the separator:%q, see param.WithSeparator()", key, item, sep)
+		}
+	}
+	return strings.Join(v.List, sep), nil
+}
+
+// configFileMapEntry finds the map param of a key like `labels.env`. entry is `env`, with the case kept.
+func configFileMapEntry(known map[string]param.Param, key string) (mapKey string, entry string, found bool) {
+	lower := strings.ToLower(key)
+	for i := len(lower) - 1; i > 0; i-- {
+		if lower[i] != '.' {
+			continue
+		}
+		if p, ok := known[lower[:i]]; ok && p.IsMap() {
+			return lower[:i], key[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// configFileKeys lists all the keys matching a param, in this Manager and all the SubCommands.
+func (c *Manager) configFileKeys(subCommands []subcommand.SubCommand, res map[string]param.Param) {
+	for _, p := range c.Params {
+		for _, k := range configFileKeys(subCommands, p.Name.String()) {
+			if _, ok := res[k]; !ok {
+				res[k] = p
+			}
+		}
+	}
+	for _, subCmd := range c.subCommandsOrder {
+		c.SubCommands[subCmd].configFileKeys(append(append([]subcommand.SubCommand{}, subCommands...), subCmd), res)
+	}
+}
+
+// configFileKeys are the keys matching a param, most specific first: `subcommand1.subcommand2.name` then `name`.
+func configFileKeys(subCommands []subcommand.SubCommand, name string) []string {
+	path := []string{}
+	for _, subCmd := range subCommands {
+		if subCmd != subCommandLevel0 {
+			path = append(path, subCmd.String())
+		}
+	}
+	name = strings.ToLower(name)
+	if len(path) == 0 {
+		return []string{name}
+	}
+	return []string{strings.ToLower(strings.Join(path, ".")) + "." + name, name}
+}
+
+func (p *paramImpl) configFileValue(in initInputs) (string, bool) {
+	if !p.reads(source.ConfigFile) {
+		return "", false
+	}
+	for _, k := range configFileKeys(p.subCommands, p.Name.String()) {
+		if v, ok := in.configFile[k]; ok {
+			return v, true
+		}
+	}
+	return "", false
+}
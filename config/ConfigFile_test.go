@@ -0,0 +1,164 @@
+package config
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vincentkerdraon/configo/config/configfile"
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param"
+)
+
+func TestManager_WithConfigFile(t *testing.T) {
+	type DB struct {
+		Host string
+		Port int `default:"5432"`
+	}
+	type Deploy struct {
+		Region string
+	}
+
+	dir := t.TempDir()
+	pathINI := filepath.Join(dir, "conf.ini")
+	if err := os.WriteFile(pathINI, []byte("[db]\nhost=localhost\n[deploy]\nregion=ca-central-1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pathJSON := filepath.Join(dir, "conf.json")
+	if err := os.WriteFile(pathJSON, []byte(`{"db":{"host":"db.local","port":"5433"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pathUnknown := filepath.Join(dir, "unknown.json")
+	if err := os.WriteFile(pathUnknown, []byte(`{"db":{"hots":"typo"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	newManager := func(db *DB, deploy *Deploy, opts ...configOptionsF) *Manager {
+		cDeploy, err := New(WithParamsFromStructTag(deploy, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := New(append(opts, WithParamsFromStructTag(db, "db."), WithSubCommand("deploy", cDeploy))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	t.Run("INI then JSON, flag wins", func(t *testing.T) {
+		db, deploy := DB{}, Deploy{}
+		c := newManager(&db, &deploy, WithConfigFile(pathINI, configfile.INI), WithConfigFile(pathJSON, configfile.JSON))
+		if err := c.Init(context.Background(), WithInputArgs([]string{"deploy", "-db.Port=1"})); err != nil {
+			t.Fatal(err)
+		}
+		want := DB{Host: "db.local", Port: 1}
+		if db != want || deploy.Region != "ca-central-1" {
+			t.Errorf("config file\ngot =%+v %+v\nwant=%+v {Region:ca-central-1}", db, deploy, want)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		db, deploy := DB{}, Deploy{}
+		c := newManager(&db, &deploy, WithConfigFile(pathUnknown, configfile.JSON))
+		err := c.Init(context.Background(), WithInputArgs([]string{}))
+		if !stderrors.Is(err, errors.ErrConfigFileUnknownKey) {
+			t.Errorf("config file unknown key\ngot =%v\nwant=%v", err, errors.ErrConfigFileUnknownKey)
+		}
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		path := filepath.Join(dir, "reload.json")
+		if err := os.WriteFile(path, []byte(`{"P":"v1"}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		var got string
+		p, _ := param.New("P", func(s string) error { got = s; return nil })
+		c, _ := New(WithParams(p), WithConfigFile(path, configfile.JSON))
+		if err := c.Init(context.Background(), WithInputArgs([]string{})); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(`{"P":"v2"}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Reload(context.Background(), WithReloadConfigFile(true)); err != nil {
+			t.Fatal(err)
+		}
+		if got != "v2" {
+			t.Errorf("config file reload\ngot =%q\nwant=%q", got, "v2")
+		}
+	})
+
+	t.Run("arrays and objects", func(t *testing.T) {
+		writeJSON := func(t *testing.T, content string) string {
+			path := filepath.Join(t.TempDir(), "conf.json")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			return path
+		}
+		tests := []struct {
+			name       string
+			content    string
+			wantPeers  []string
+			wantLabels map[string]string
+			wantErr    string
+		}{
+			{
+				name:       "separator and map",
+				content:    `{"Peers":["a,1","b,2"],"Labels":{"Env":"prod","team":"core"}}`,
+				wantPeers:  []string{"a,1", "b,2"},
+				wantLabels: map[string]string{"Env": "prod", "team": "core"},
+			},
+			{
+				name:       "map as string",
+				content:    `{"Labels":"env=prod"}`,
+				wantLabels: map[string]string{"env": "prod"},
+			},
+			{
+				name:    "item with separator",
+				content: `{"Peers":["a;b"]}`,
+				wantErr: `item:"a;b" contains the separator:";"`,
+			},
+			{
+				name:    "array for a single value",
+				content: `{"Name":["a","b"]}`,
+				wantErr: `got an array, param:"Name" is not a slice or a map`,
+			},
+			{
+				name:    "object for a single value",
+				content: `{"Name":{"a":"b"}}`,
+				wantErr: `unknown key: [name.a]`,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var peers []string
+				var labels map[string]string
+				pPeers, _ := param.NewStringSlice("Peers", func(v []string) error { peers = v; return nil }, param.WithSeparator(";"))
+				pLabels, _ := param.NewStringMap("Labels", func(v map[string]string) error { labels = v; return nil })
+				pName, _ := param.NewString("Name", func(string) error { return nil })
+				c, err := New(WithParams(pPeers, pLabels, pName), WithConfigFile(writeJSON(t, tt.content), configfile.JSON))
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = c.Init(context.Background(), WithInputArgs([]string{}))
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Errorf("config file\ngot =%v\nwant=%q", err, tt.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(peers, tt.wantPeers) || !reflect.DeepEqual(labels, tt.wantLabels) {
+					t.Errorf("config file\ngot =%q %q\nwant=%q %q", peers, labels, tt.wantPeers, tt.wantLabels)
+				}
+			})
+		}
+	})
+}
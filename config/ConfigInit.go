@@ -1,11 +1,25 @@
 package config
 
+import (
+	"io"
+)
+
 type (
 	Init struct {
 		//InputArgs to read the flag input
 		//
 		// default: os.Args[1:] (with Args[0] being the name of the program)
 		InputArgs []string //TODO subcommand mandatory, subcommand used?
+
+		//Output is where to write when Init() answers directly, like the shell completion or the help.
+		//
+		// default: os.Stdout
+		Output io.Writer
+
+		//Exit is called after Init() answered directly, like the shell completion or the help.
+		//
+		// default: os.Exit
+		Exit func(code int)
 	}
 
 	configInitOptions func(r *Init) error
@@ -20,3 +34,23 @@ func WithInputArgs(args []string) configInitOptions {
 		return nil
 	}
 }
+
+// WithOutput to define where to write when Init() answers directly, like the shell completion or the help.
+//
+// default: os.Stdout
+func WithOutput(w io.Writer) configInitOptions {
+	return func(ci *Init) error {
+		ci.Output = w
+		return nil
+	}
+}
+
+// WithExit to define what to do after Init() answered directly, like the shell completion or the help.
+//
+// default: os.Exit
+func WithExit(f func(code int)) configInitOptions {
+	return func(ci *Init) error {
+		ci.Exit = f
+		return nil
+	}
+}
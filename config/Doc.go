@@ -0,0 +1,297 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/source"
+	"github.com/vincentkerdraon/configo/config/subcommand"
+)
+
+// docCommand is one command (level 0 or subcommand) to document.
+type docCommand struct {
+	path    []subcommand.SubCommand
+	manager *Manager
+	//priority inherited from the parents, unless redefined.
+	priority []source.Source
+}
+
+// docParamLine is one piece of information about a param, like `Flag: -Town`.
+type docParamLine struct {
+	name  string
+	value string
+	//code when the value is a literal to show as code.
+	code []string
+}
+
+// WriteMarkdown writes the documentation of all the params and subcommands.
+//
+// The output is stable (sorted by name), so it can be committed and compared.
+func (c *Manager) WriteMarkdown(w io.Writer, program string) error {
+	if program == "" {
+		return errors.ConfigError{Err: fmt.Errorf("mandatory program name")}
+	}
+	var b strings.Builder
+	for i, cmd := range c.docCommands(nil, nil) {
+		name := docCommandName(program, cmd.path)
+		if i == 0 {
+			fmt.Fprintf(&b, "# %s\n\n", name)
+		} else {
+			fmt.Fprintf(&b, "## Command `%s`\n\n", name)
+		}
+		if cmd.manager.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", cmd.manager.Description)
+		}
+		if len(cmd.path) > 0 && len(cmd.manager.Aliases) > 0 {
+			fmt.Fprintf(&b, "Aliases: `%s`\n\n", strings.ReplaceAll(joinSubCommands(cmd.manager.Aliases), ", ", "`, `"))
+		}
+		if len(cmd.path) > 0 && cmd.manager.Deprecated != "" {
+			fmt.Fprintf(&b, "Deprecated, %s.\n\n", cmd.manager.Deprecated)
+		}
+		if len(cmd.manager.Args) > 0 {
+			fmt.Fprintf(&b, "Args: `%s`\n\n", cmd.manager.argsUsage())
+			for _, a := range cmd.manager.Args {
+				if a.Desc != "" {
+					fmt.Fprintf(&b, "- `%s`: %s (%s)\n", a.Name, a.Desc, a.arity())
+				} else {
+					fmt.Fprintf(&b, "- `%s`: %s\n", a.Name, a.arity())
+				}
+			}
+			b.WriteString("\n")
+		}
+		if len(cmd.path) > 0 {
+			fmt.Fprintf(&b, "Inherits the params of `%s`, except the local ones.\n\n", docCommandName(program, cmd.path[:len(cmd.path)-1]))
+		}
+		if len(cmd.manager.SubCommands) > 0 {
+			b.WriteString("Commands:\n\n")
+			for _, subCmd := range cmd.manager.sortedSubCommands() {
+				if cmd.manager.SubCommands[subCmd].Hidden {
+					continue
+				}
+				fmt.Fprintf(&b, "- `%s`\n", docCommandName(program, append(append([]subcommand.SubCommand{}, cmd.path...), subCmd)))
+			}
+			b.WriteString("\n")
+		}
+		for _, p := range cmd.manager.sortedParams() {
+			if p.IsHidden {
+				continue
+			}
+			fmt.Fprintf(&b, "### `%s`\n\n", p.Name)
+			if p.Desc != "" {
+				fmt.Fprintf(&b, "%s\n\n", p.Desc)
+			}
+			for _, l := range docParamLines(p, cmd.priority, c.FlagStyle, c.naming(cmd.path)) {
+				values := []string{l.value}
+				if l.code != nil {
+					values = []string{}
+					for _, v := range l.code {
+						values = append(values, "`"+v+"`")
+					}
+				}
+				fmt.Fprintf(&b, "- %s: %s\n", l.name, strings.Join(values, ", "))
+			}
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, strings.TrimRight(b.String(), "\n")+"\n")
+	return err
+}
+
+// WriteManPage writes the documentation of all the params and subcommands as a troff man page.
+//
+// The output is stable (sorted by name), so it can be committed and compared.
+func (c *Manager) WriteManPage(w io.Writer, program string, section int) error {
+	if program == "" {
+		return errors.ConfigError{Err: fmt.Errorf("mandatory program name")}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH %s %d\n", manEscape(strings.ToUpper(program)), section)
+	b.WriteString(".SH NAME\n")
+	if c.Description != "" {
+		fmt.Fprintf(&b, "%s \\- %s\n", manEscape(program), manEscape(c.Description))
+	} else {
+		fmt.Fprintf(&b, "%s\n", manEscape(program))
+	}
+	b.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&b, ".B %s\n", manEscape(program))
+	if len(c.SubCommands) > 0 {
+		b.WriteString("[\\fIcommand\\fR] ")
+	}
+	b.WriteString("[\\fIoptions\\fR]\n")
+
+	for i, cmd := range c.docCommands(nil, nil) {
+		if i == 0 {
+			b.WriteString(".SH OPTIONS\n")
+		} else {
+			if i == 1 {
+				b.WriteString(".SH COMMANDS\n")
+			}
+			fmt.Fprintf(&b, ".SS %s\n", manEscape(docCommandName(program, cmd.path)))
+			if cmd.manager.Description != "" {
+				fmt.Fprintf(&b, "%s\n", manEscape(cmd.manager.Description))
+			}
+		}
+		for _, p := range cmd.manager.sortedParams() {
+			if p.IsHidden {
+				continue
+			}
+			pi := paramImpl{Param: p, priority: paramPriority(p, cmd.priority), flagStyle: c.FlagStyle, naming: c.naming(cmd.path)}
+			if p.Flag.Use && pi.reads(source.Flag) && pi.isBool() {
+				fmt.Fprintf(&b, ".TP\n.B %s\n", manEscape(strings.Join(pi.flagForms(), ", ")))
+			} else if p.Flag.Use && pi.reads(source.Flag) {
+				fmt.Fprintf(&b, ".TP\n.B %s \\fIvalue\\fR\n", manEscape(strings.Join(pi.flagForms(), ", ")))
+			} else {
+				fmt.Fprintf(&b, ".TP\n.B %s\n", manEscape(p.Name.String()))
+			}
+			if p.Desc != "" {
+				fmt.Fprintf(&b, "%s\n", manEscape(p.Desc))
+			}
+			for _, l := range docParamLines(p, cmd.priority, c.FlagStyle, c.naming(cmd.path)) {
+				v := l.value
+				if l.code != nil {
+					v = strings.Join(l.code, ", ")
+				}
+				fmt.Fprintf(&b, ".br\n%s: %s\n", manEscape(l.name), manEscape(v))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// docCommands lists this Manager and all the SubCommands, depth first, sorted by name.
+//
+// priority is inherited from the parents, like in Init().
+func (c *Manager) docCommands(path []subcommand.SubCommand, priority []source.Source) []docCommand {
+	priority = c.inheritPriority(priority)
+	res := []docCommand{{path: path, manager: c, priority: priority}}
+	for _, subCmd := range c.sortedSubCommands() {
+		if c.SubCommands[subCmd].Hidden {
+			continue
+		}
+		res = append(res, c.SubCommands[subCmd].docCommands(append(append([]subcommand.SubCommand{}, path...), subCmd), priority)...)
+	}
+	return res
+}
+
+func (c *Manager) sortedParams() []param.Param {
+	res := make([]param.Param, 0, len(c.Params))
+	for _, p := range c.Params {
+		res = append(res, p)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// visibleSubCommands are the subcommands not hidden, sorted by name.
+func (c *Manager) visibleSubCommands() []subcommand.SubCommand {
+	res := []subcommand.SubCommand{}
+	for _, subCmd := range c.sortedSubCommands() {
+		if !c.SubCommands[subCmd].Hidden {
+			res = append(res, subCmd)
+		}
+	}
+	return res
+}
+
+func (c *Manager) sortedSubCommands() []subcommand.SubCommand {
+	res := make([]subcommand.SubCommand, 0, len(c.SubCommands))
+	for subCmd := range c.SubCommands {
+		res = append(res, subCmd)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+func docCommandName(program string, path []subcommand.SubCommand) string {
+	res := program
+	for _, subCmd := range path {
+		res += " " + subCmd.String()
+	}
+	return res
+}
+
+// docParamLines with the priority of the Manager, inherited from the parents.
+func docParamLines(p param.Param, priority []source.Source, flagStyle FlagStyle, n naming) []docParamLine {
+	pi := paramImpl{Param: p, priority: paramPriority(p, priority), flagStyle: flagStyle, naming: n}
+	res := []docParamLine{}
+	if p.Flag.Use && pi.reads(source.Flag) {
+		if pi.isBool() {
+			res = append(res, docParamLine{name: "Flag (switch)", code: pi.flagForms()})
+		} else {
+			res = append(res, docParamLine{name: "Flag", code: pi.flagForms()})
+		}
+	}
+	if p.EnvVar.Use && pi.reads(source.EnvVar) {
+		res = append(res, docParamLine{name: "Env var", code: []string{pi.envVarName()}})
+	}
+	if p.Default != "" {
+		res = append(res, docParamLine{name: "Default", code: []string{pi.redact(p.Default)}})
+	}
+	if len(p.Examples) > 0 {
+		res = append(res, docParamLine{name: "Examples", code: p.Examples})
+	}
+	if len(p.EnumValues) > 0 {
+		res = append(res, docParamLine{name: "Enum values", code: p.EnumValues})
+	}
+	validations := []string{}
+	for _, v := range p.Validators {
+		if v.Desc != "" {
+			validations = append(validations, v.Desc)
+		}
+	}
+	if len(validations) > 0 {
+		res = append(res, docParamLine{name: "Validation", code: validations})
+	}
+	if p.IsMandatory {
+		res = append(res, docParamLine{name: "Mandatory", value: "yes"})
+	}
+	if len(p.Exclusive) > 0 {
+		exclusive := []string{}
+		for _, e := range p.Exclusive {
+			exclusive = append(exclusive, e.String())
+		}
+		res = append(res, docParamLine{name: "Exclusive with", code: exclusive})
+	}
+	if len(p.Requires) > 0 {
+		requires := []string{}
+		for _, r := range p.Requires {
+			requires = append(requires, r.String())
+		}
+		res = append(res, docParamLine{name: "Requires", code: requires})
+	}
+	if len(p.RequiredIf) > 0 {
+		res = append(res, docParamLine{name: "Mandatory if", code: requiredIf(p.RequiredIf)})
+	}
+	if p.IsSubCommandLocal {
+		res = append(res, docParamLine{name: "Local", value: "not available in sub commands"})
+	}
+	if p.Loader.Getter != nil && pi.reads(source.Loader) {
+		if p.Loader.SynchroFrequency == 0 {
+			res = append(res, docParamLine{name: "Loader", value: "at startup only"})
+		} else {
+			res = append(res, docParamLine{name: "Loader", value: "refresh every " + p.Loader.SynchroFrequency.String()})
+		}
+	}
+	if p.SourcePriority != nil {
+		res = append(res, docParamLine{name: "Source priority", value: source.FormatPriority(p.SourcePriority)})
+	}
+	return res
+}
+
+// manEscape escapes a text for troff.
+func manEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, ".") || strings.HasPrefix(l, "'") {
+			lines[i] = `\&` + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
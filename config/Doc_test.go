@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/source"
+)
+
+func newDocTestManager(t *testing.T) *Manager {
+	noop := func(s string) error { return nil }
+	pCity, err := param.New("City", noop,
+		param.WithDesc("City where user lives"),
+		param.WithIsMandatory(true),
+		param.WithDefault("Vancouver"),
+		param.WithExamples("Toronto", "Vancouver"),
+		param.WithFlag(param.WithFlagName("Town")),
+		param.WithEnvVar(param.WithEnvVarName("TOWN")),
+		param.WithEnumValues("Toronto", "Vancouver", "Montreal"),
+		param.WithExclusive("Age"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pAge, err := param.New("Age", noop,
+		param.WithLoader(func(ctx context.Context) (string, error) { return "35", nil }, param.WithSynchroFrequency(time.Hour)),
+		param.WithIsSubCommandLocal(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pRegion, err := param.New("Region", noop, param.WithFlag(param.WithReadFlag(false)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cDeploy, err := New(WithParams(pRegion), WithDescription("Deploy the app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(pCity, pAge), WithSubCommand("deploy", cDeploy), WithDescription("A city reader"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestManager_WriteMarkdown(t *testing.T) {
+	c := newDocTestManager(t)
+	var b strings.Builder
+	if err := c.WriteMarkdown(&b, "tool"); err != nil {
+		t.Fatal(err)
+	}
+	want := "# tool\n\nA city reader\n\nCommands:\n\n- `tool deploy`\n\n" +
+		"### `Age`\n\n- Flag: `-Age`\n- Env var: `Age`\n- Local: not available in sub commands\n- Loader: refresh every 1h0m0s\n\n" +
+		"### `City`\n\nCity where user lives\n\n- Flag: `-Town`\n- Env var: `TOWN`\n- Default: `Vancouver`\n- Examples: `Toronto`, `Vancouver`\n" +
+		"- Enum values: `Toronto`, `Vancouver`, `Montreal`\n- Mandatory: yes\n- Exclusive with: `Age`\n\n" +
+		"## Command `tool deploy`\n\nDeploy the app\n\nInherits the params of `tool`, except the local ones.\n\n" +
+		"### `Region`\n\n- Env var: `Region`\n"
+	if b.String() != want {
+		t.Errorf("WriteMarkdown\ngot =%s\ngot =%q\nwant=%q", b.String(), b.String(), want)
+	}
+}
+
+func TestManager_WriteManPage(t *testing.T) {
+	c := newDocTestManager(t)
+	var b strings.Builder
+	if err := c.WriteManPage(&b, "tool", 1); err != nil {
+		t.Fatal(err)
+	}
+	want := ".TH TOOL 1\n.SH NAME\ntool \\- A city reader\n.SH SYNOPSIS\n.B tool\n[\\fIcommand\\fR] [\\fIoptions\\fR]\n" +
+		".SH OPTIONS\n.TP\n.B \\-Age \\fIvalue\\fR\n.br\nFlag: \\-Age\n.br\nEnv var: Age\n.br\nLocal: not available in sub commands\n.br\nLoader: refresh every 1h0m0s\n" +
+		".TP\n.B \\-Town \\fIvalue\\fR\nCity where user lives\n.br\nFlag: \\-Town\n.br\nEnv var: TOWN\n.br\nDefault: Vancouver\n.br\nExamples: Toronto, Vancouver\n" +
+		".br\nEnum values: Toronto, Vancouver, Montreal\n.br\nMandatory: yes\n.br\nExclusive with: Age\n" +
+		".SH COMMANDS\n.SS tool deploy\nDeploy the app\n.TP\n.B Region\n.br\nEnv var: Region\n"
+	if b.String() != want {
+		t.Errorf("WriteManPage\ngot =%s\ngot =%q\nwant=%q", b.String(), b.String(), want)
+	}
+}
+
+func TestManager_WriteMarkdown_inheritedPriority(t *testing.T) {
+	noop := func(s string) error { return nil }
+	pRegion, err := param.New("Region", noop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pZone, err := param.New("Zone", noop, param.WithSourcePriority(source.Flag, source.Default))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cDeploy, err := New(WithParams(pRegion, pZone))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithSubCommand("deploy", cDeploy), WithSourcePriority(source.EnvVar, source.Default))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var md, man strings.Builder
+	if err := c.WriteMarkdown(&md, "tool"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WriteManPage(&man, "tool", 1); err != nil {
+		t.Fatal(err)
+	}
+	wantMD := "### `Region`\n\n- Env var: `Region`\n\n### `Zone`\n\n- Flag: `-Zone`\n- Source priority: Flag > Default\n"
+	if !strings.HasSuffix(md.String(), wantMD) {
+		t.Errorf("WriteMarkdown\ngot =%q\nwant suffix=%q", md.String(), wantMD)
+	}
+	wantMan := ".TP\n.B Region\n.br\nEnv var: Region\n.TP\n.B \\-Zone \\fIvalue\\fR\n.br\nFlag: \\-Zone\n"
+	if !strings.Contains(man.String(), wantMan) {
+		t.Errorf("WriteManPage\ngot =%q\nwant=%q", man.String(), wantMan)
+	}
+}
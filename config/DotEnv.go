@@ -0,0 +1,36 @@
+package config
+
+import (
+	"github.com/vincentkerdraon/configo/config/dotenv"
+	"github.com/vincentkerdraon/configo/config/errors"
+)
+
+// WithDotEnvFiles reads `KEY=VALUE` files during Init(), a layer below the real environment variables.
+//
+// Can be called multiple times, the last file overrides the previous ones. Missing files are skipped.
+// Like env vars, `FOO_FILE=/run/secrets/foo` reads the value of FOO in the file.
+func WithDotEnvFiles(paths ...string) configOptionsF {
+	return func(c *Manager) error {
+		c.DotEnvFiles = append(c.DotEnvFiles, paths...)
+		return nil
+	}
+}
+
+func (c *Manager) readDotEnvFiles() (map[string]string, error) {
+	if len(c.DotEnvFiles) == 0 {
+		return nil, nil
+	}
+	res, err := dotenv.Read(c.DotEnvFiles...)
+	if err != nil {
+		return nil, errors.ConfigError{Err: err}
+	}
+	return res, nil
+}
+
+// loadDotEnv reads the env var in the .env files, or the file named by `NAME_FILE`.
+func (p paramImpl) loadDotEnv(in initInputs) (string, error) {
+	if len(in.dotEnv) == 0 {
+		return "", nil
+	}
+	return lookupEnvVar(p.envVarName(), func(name string) string { return in.dotEnv[name] })
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vincentkerdraon/configo/config/param/source"
+)
+
+func TestManager_WithDotEnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	secretPath := filepath.Join(dir, "secret")
+	if err := os.WriteFile(secretPath, []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	dotEnvPath := filepath.Join(dir, ".env")
+	if err := os.WriteFile(dotEnvPath, []byte("export CONFIGO_TEST_NAME=\"from dotenv\"\nCONFIGO_TEST_CITY=Toronto\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIGO_TEST_CITY", "Vancouver")
+	t.Setenv("CONFIGO_TEST_PASSWORD_FILE", secretPath)
+
+	conf := struct {
+		Name     string `envVar:"CONFIGO_TEST_NAME"`
+		City     string `envVar:"CONFIGO_TEST_CITY"`
+		Password string `envVar:"CONFIGO_TEST_PASSWORD"`
+	}{}
+	c, err := New(WithParamsFromStructTag(&conf, ""), WithDotEnvFiles(dotEnvPath, filepath.Join(dir, "missing.env")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{})); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Name != "from dotenv" || conf.City != "Vancouver" || conf.Password != "s3cr3t" {
+		t.Errorf("dot env\ngot =%+v\nwant={Name:from dotenv City:Vancouver Password:s3cr3t}", conf)
+	}
+	if p, _ := c.Snapshot().Get("City"); p.Source != source.EnvVar || len(p.Overridden) != 1 || p.Overridden[0].Source != source.DotEnv {
+		t.Errorf("dot env snapshot\ngot =%+v", p)
+	}
+
+	t.Setenv("CONFIGO_TEST_PASSWORD_FILE", filepath.Join(dir, "missing"))
+	if err := c.Init(context.Background(), WithInputArgs([]string{})); err == nil {
+		t.Errorf("expect error when _FILE is missing")
+	}
+}
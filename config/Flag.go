@@ -0,0 +1,168 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+)
+
+type (
+	// FlagStyle is how the command line flags are parsed.
+	FlagStyle string
+
+	// flagDef is a flag to parse, before knowing the parsing style.
+	flagDef struct {
+		name  string
+		short rune
+		value *flagValue
+		//envVar of the same param, only to suggest a flag.
+		envVar string
+	}
+)
+
+const (
+	// FlagStyleGo uses the std flag package: `-name=value`, `-name value` or `--name=value`. A switch with `-name`.
+	//
+	// A short name is an alias: `-v`, `-o value`.
+	FlagStyleGo FlagStyle = "go"
+
+	// FlagStyleGNU (POSIX/GNU getopt_long): `--name=value`, `--name value`, `-o value`, `-ovalue`.
+	// Short switches can be bundled: `-vx` is `-v -x`. Flags can be mixed with the positional args. `--` ends the flags.
+	FlagStyleGNU FlagStyle = "gnu"
+)
+
+// errFlagNeedsArgument is the std flag package message, also used for FlagStyleGNU.
+const errFlagNeedsArgument = "flag needs an argument:"
+
+// WithFlagStyle chooses how the command line flags are parsed. Applies to the SubCommands.
+//
+// default: FlagStyleGo
+func WithFlagStyle(s FlagStyle) configOptionsF {
+	return func(c *Manager) error {
+		if s != FlagStyleGo && s != FlagStyleGNU {
+			return errors.ConfigError{Err: fmt.Errorf("unknown flag style:%q, expect one of:%v", s, []FlagStyle{FlagStyleGo, FlagStyleGNU})}
+		}
+		c.FlagStyle = s
+		return nil
+	}
+}
+
+// parseFlags sets the flag values. Returns the args after the flags, and if they are after `--`.
+//
+// interspersed to accept flags mixed with the other args, only with FlagStyleGNU. Otherwise stops at the first arg not being a flag.
+func parseFlags(style FlagStyle, defs []*flagDef, args []string, interspersed bool) (remaining []string, terminated bool, _ error) {
+	long := map[string]*flagDef{}
+	short := map[rune]*flagDef{}
+	for _, d := range defs {
+		if _, f := long[d.name]; f {
+			return nil, false, errors.ConfigError{Err: fmt.Errorf("flag:%q defined 2 times", d.name)}
+		}
+		long[d.name] = d
+		if d.short == 0 {
+			continue
+		}
+		if _, f := short[d.short]; f {
+			return nil, false, errors.ConfigError{Err: fmt.Errorf("flag short name:%q defined 2 times", string(d.short))}
+		}
+		short[d.short] = d
+	}
+	if style == FlagStyleGNU {
+		return parseFlagsGNU(long, short, args, interspersed)
+	}
+
+	//using the std go flag lib is a bit annoying. But std are good.
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	for _, d := range defs {
+		fs.Var(d.value, d.name, "")
+		if d.short == 0 {
+			continue
+		}
+		if _, f := long[string(d.short)]; f {
+			return nil, false, errors.ConfigError{Err: fmt.Errorf("flag short name:%q is also a flag name", string(d.short))}
+		}
+		fs.Var(d.value, string(d.short), "")
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, false, err
+	}
+	remaining = fs.Args()
+	//The std lib drops the `--`. (A flag value `--` must be written `-name=--`)
+	terminated = len(remaining) < len(args) && args[len(args)-len(remaining)-1] == "--"
+	return remaining, terminated, nil
+}
+
+// parseFlagsGNU is like getopt_long. Everything after `--` is not a flag.
+//
+// Errors are using the same messages as the std flag package.
+func parseFlagsGNU(long map[string]*flagDef, short map[rune]*flagDef, args []string, interspersed bool) (remaining []string, terminated bool, _ error) {
+	remaining = []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(remaining, args[i+1:]...), true, nil
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			if !interspersed {
+				return args[i:], false, nil
+			}
+			remaining = append(remaining, arg)
+			continue
+		}
+
+		if strings.HasPrefix(arg, "--") {
+			name, value, hasValue := strings.Cut(arg[2:], "=")
+			d, f := long[name]
+			if !f {
+				return nil, false, fmt.Errorf("%s --%s", errFlagProvidedNotDefined, name)
+			}
+			if !hasValue && d.value.isBool {
+				value, hasValue = "true", true
+			}
+			if !hasValue {
+				if i+1 >= len(args) {
+					return nil, false, fmt.Errorf("%s --%s", errFlagNeedsArgument, name)
+				}
+				i++
+				value = args[i]
+			}
+			if err := d.value.Set(value); err != nil {
+				return nil, false, fmt.Errorf("invalid value %q for flag --%s: %w", value, name, err)
+			}
+			continue
+		}
+
+		//Short names, maybe bundled: `-vx`, `-ovalue`, `-vo value`
+		shorts := []rune(arg[1:])
+		for j := 0; j < len(shorts); j++ {
+			d, f := short[shorts[j]]
+			if !f {
+				return nil, false, fmt.Errorf("%s -%s", errFlagProvidedNotDefined, string(shorts[j]))
+			}
+			rest := string(shorts[j+1:])
+			if d.value.isBool && !strings.HasPrefix(rest, "=") {
+				if err := d.value.Set("true"); err != nil {
+					return nil, false, err
+				}
+				continue
+			}
+			//The rest of the arg is the value.
+			value := strings.TrimPrefix(rest, "=")
+			if rest == "" {
+				if i+1 >= len(args) {
+					return nil, false, fmt.Errorf("%s -%s", errFlagNeedsArgument, string(shorts[j]))
+				}
+				i++
+				value = args[i]
+			}
+			if err := d.value.Set(value); err != nil {
+				return nil, false, fmt.Errorf("invalid value %q for flag -%s: %w", value, string(shorts[j]), err)
+			}
+			break
+		}
+	}
+	return remaining, false, nil
+}
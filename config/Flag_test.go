@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_parseFlags(t *testing.T) {
+	tests := []struct {
+		name           string
+		style          FlagStyle
+		interspersed   bool
+		args           []string
+		wantValues     string
+		wantRemaining  []string
+		wantTerminated bool
+		wantErr        bool
+	}{
+		{
+			name:          "gnu long",
+			style:         FlagStyleGNU,
+			args:          []string{"--output=a.txt", "--level", "3", "--verbose"},
+			wantValues:    "output:a.txt level:3 verbose:true extra:",
+			wantRemaining: []string{},
+		},
+		{
+			name:          "gnu short",
+			style:         FlagStyleGNU,
+			args:          []string{"-o", "a.txt", "-l3", "-v"},
+			wantValues:    "output:a.txt level:3 verbose:true extra:",
+			wantRemaining: []string{},
+		},
+		{
+			name:          "gnu bundled switches and value",
+			style:         FlagStyleGNU,
+			args:          []string{"-vxo", "a.txt", "pos"},
+			wantValues:    "output:a.txt level: verbose:true extra:true",
+			wantRemaining: []string{"pos"},
+		},
+		{
+			name:          "gnu bundled with value in the same arg",
+			style:         FlagStyleGNU,
+			args:          []string{"-vl=3"},
+			wantValues:    "output: level:3 verbose:true extra:",
+			wantRemaining: []string{},
+		},
+		{
+			name:           "gnu terminator",
+			style:          FlagStyleGNU,
+			args:           []string{"-v", "--", "-x", "--level=3"},
+			wantValues:     "output: level: verbose:true extra:",
+			wantRemaining:  []string{"-x", "--level=3"},
+			wantTerminated: true,
+		},
+		{
+			name:           "go terminator",
+			style:          FlagStyleGo,
+			args:           []string{"-v", "--", "-x"},
+			wantValues:     "output: level: verbose:true extra:",
+			wantRemaining:  []string{"-x"},
+			wantTerminated: true,
+		},
+		{
+			name:           "gnu flags mixed with args",
+			style:          FlagStyleGNU,
+			interspersed:   true,
+			args:           []string{"a.txt", "-v", "b.txt", "--", "-x"},
+			wantValues:     "output: level: verbose:true extra:",
+			wantRemaining:  []string{"a.txt", "b.txt", "-x"},
+			wantTerminated: true,
+		},
+		{
+			name:          "gnu stops at the first arg when not interspersed",
+			style:         FlagStyleGNU,
+			args:          []string{"-v", "a.txt", "-x"},
+			wantValues:    "output: level: verbose:true extra:",
+			wantRemaining: []string{"a.txt", "-x"},
+		},
+		{
+			name:          "go stops at the first arg",
+			style:         FlagStyleGo,
+			args:          []string{"-v", "a.txt", "-x"},
+			wantValues:    "output: level: verbose:true extra:",
+			wantRemaining: []string{"a.txt", "-x"},
+		},
+		{
+			name:          "gnu switch with explicit value",
+			style:         FlagStyleGNU,
+			args:          []string{"--verbose=false", "-x=false"},
+			wantValues:    "output: level: verbose:false extra:false",
+			wantRemaining: []string{},
+		},
+		{
+			name:          "gnu single dash is a short name",
+			style:         FlagStyleGNU,
+			args:          []string{"-output=a.txt"},
+			wantValues:    "output:utput=a.txt level: verbose: extra:",
+			wantRemaining: []string{},
+		},
+		{
+			name:    "gnu unknown",
+			style:   FlagStyleGNU,
+			args:    []string{"--unknown"},
+			wantErr: true,
+		},
+		{
+			name:    "gnu missing value",
+			style:   FlagStyleGNU,
+			args:    []string{"-vo"},
+			wantErr: true,
+		},
+		{
+			name:          "go short alias",
+			style:         FlagStyleGo,
+			args:          []string{"-o", "a.txt", "-v", "--level=3"},
+			wantValues:    "output:a.txt level:3 verbose:true extra:",
+			wantRemaining: []string{},
+		},
+		{
+			name:    "go no bundling",
+			style:   FlagStyleGo,
+			args:    []string{"-vx"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, level, verbose, extra := &flagValue{}, &flagValue{}, &flagValue{isBool: true}, &flagValue{isBool: true}
+			defs := []*flagDef{
+				{name: "output", short: 'o', value: output},
+				{name: "level", short: 'l', value: level},
+				{name: "verbose", short: 'v', value: verbose},
+				{name: "extra", short: 'x', value: extra},
+			}
+			gotRemaining, gotTerminated, err := parseFlags(tt.style, defs, tt.args, tt.interspersed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			gotValues := fmt.Sprintf("output:%s level:%s verbose:%s extra:%s", output, level, verbose, extra)
+			if gotValues != tt.wantValues {
+				t.Errorf("parseFlags() values\ngot =%s\nwant=%s", gotValues, tt.wantValues)
+			}
+			if fmt.Sprintf("%q", gotRemaining) != fmt.Sprintf("%q", tt.wantRemaining) {
+				t.Errorf("parseFlags() remaining\ngot =%q\nwant=%q", gotRemaining, tt.wantRemaining)
+			}
+			if gotTerminated != tt.wantTerminated {
+				t.Errorf("parseFlags() terminated\ngot =%t\nwant=%t", gotTerminated, tt.wantTerminated)
+			}
+		})
+	}
+}
+
+func Test_parseFlags_duplicate(t *testing.T) {
+	defs := []*flagDef{
+		{name: "verbose", short: 'v', value: &flagValue{}},
+		{name: "version", short: 'v', value: &flagValue{}},
+	}
+	if _, _, err := parseFlags(FlagStyleGNU, defs, nil, false); err == nil {
+		t.Errorf("parseFlags() expect error for duplicate short name")
+	}
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// helpFlag is the built-in flag printing the usage: `-h`, `-help` or `--help`. Unless a param already uses these names.
+	helpFlag = "help"
+	// helpCommand is the built-in command printing the usage of a subcommand, like `tool help deploy`. Unless a subcommand already uses this name.
+	helpCommand = "help"
+	// tabWidth to measure the usage lines.
+	tabWidth = 8
+)
+
+// ansiEscape matches the ANSI colors.
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// helpKey is the flag value of the built-in help flag. Not a param, so no level.
+var helpKey = flagKey{depth: -1, name: helpFlag}
+
+// WithUsageWidth is the line width of the usage. The longer lines are wrapped.
+//
+// default: 0, no wrapping. Except the built-in help written to a terminal, wrapped at the terminal width (or the env var COLUMNS).
+func WithUsageWidth(width int) configOptionsF {
+	return func(c *Manager) error {
+		c.UsageWidth = width
+		return nil
+	}
+}
+
+// withHelpFlag adds the built-in help flag, when the names are not used by a param.
+func withHelpFlag(defs []*flagDef, flags map[flagKey]*flagValue) []*flagDef {
+	short := 'h'
+	for _, d := range defs {
+		if d.name == helpFlag {
+			return defs
+		}
+		if d.name == "h" || d.short == 'h' {
+			short = 0
+		}
+	}
+	if flags[helpKey] == nil {
+		flags[helpKey] = &flagValue{isBool: true}
+	}
+	return append(defs, &flagDef{name: helpFlag, short: short, value: flags[helpKey]})
+}
+
+// isHelpFlag when the built-in help flag is in the command line.
+func isHelpFlag(flags map[flagKey]*flagValue) bool {
+	fv := flags[helpKey]
+	if fv == nil || !fv.isSet {
+		return false
+	}
+	b, err := strconv.ParseBool(fv.value)
+	return err == nil && b
+}
+
+// isHelpCommand when the word is the built-in help command, like `tool help deploy`.
+func (c *Manager) isHelpCommand(word string) bool {
+	if word != helpCommand || len(c.Args) > 0 {
+		return false
+	}
+	_, _, f := c.lookupSubCommand(helpCommand)
+	return !f
+}
+
+// wrap the usage lines longer than the width, see WithUsageWidth().
+func (c Manager) wrap(usage string) string {
+	return wrapLines(usage, c.UsageWidth)
+}
+
+// helpRenderer writes the built-in help. The default renderer wraps at the terminal width, when writing to a terminal.
+func (c Manager) helpRenderer(w io.Writer) UsageRenderer {
+	if c.UsageRenderer != nil || c.UsageWidth != 0 {
+		return c.usageRenderer()
+	}
+	return TextRenderer{Width: terminalWidth(w)}
+}
+
+// terminalWidth is the env var COLUMNS, or the width of the terminal. 0 when w is not a terminal.
+func terminalWidth(w io.Writer) int {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0
+	}
+	width := terminalWidthFd(f.Fd())
+	if width <= 0 {
+		return 0
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return width
+}
+
+// wrapLines splits the lines longer than width, between words.
+// The next lines keep the indentation, plus one tab.
+func wrapLines(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	res := []string{}
+	for _, line := range strings.Split(s, "\n") {
+		indent := line[:len(line)-len(strings.TrimLeft(line, "\t"))]
+		current := indent
+		for i, word := range strings.Split(line[len(indent):], " ") {
+			switch {
+			case i == 0:
+				current += word
+			case displayWidth(current+" "+word) > width && strings.TrimLeft(current, "\t") != "":
+				res = append(res, current)
+				current = indent + "\t" + word
+			default:
+				current += " " + word
+			}
+		}
+		res = append(res, current)
+	}
+	return strings.Join(res, "\n")
+}
+
+// displayWidth is the number of columns, without the ANSI colors. See ColorRenderer.
+func displayWidth(s string) int {
+	s = ansiEscape.ReplaceAllString(s, "")
+	tabs := strings.Count(s, "\t")
+	return utf8.RuneCountInString(s) - tabs + tabs*tabWidth
+}
@@ -2,25 +2,24 @@ package config
 
 import (
 	"context"
-	"flag"
 	"fmt"
-	"io"
 	"os"
-	"strings"
 	"time"
 
 	"log/slog"
 
 	"github.com/vincentkerdraon/configo/config/errors"
 	"github.com/vincentkerdraon/configo/config/param/paramname"
+	"github.com/vincentkerdraon/configo/config/param/source"
 	"github.com/vincentkerdraon/configo/config/subcommand"
+	"github.com/vincentkerdraon/configo/lock"
 )
 
 const subCommandLevel0 subcommand.SubCommand = ""
 
 // Init reads the params for the first time and parses the flags
 func (c *Manager) Init(ctx context.Context, opts ...configInitOptions) error {
-	ci := Init{InputArgs: os.Args[1:]}
+	ci := Init{InputArgs: os.Args[1:], Output: os.Stdout, Exit: os.Exit}
 	for _, opt := range opts {
 		if opt == nil {
 			continue
@@ -30,25 +29,53 @@ func (c *Manager) Init(ctx context.Context, opts ...configInitOptions) error {
 		}
 	}
 
+	//Hidden mode, called by the shell completion scripts.
+	if len(ci.InputArgs) > 0 && ci.InputArgs[0] == completeCommand {
+		for _, s := range c.complete(ci.InputArgs[1:]) {
+			fmt.Fprintln(ci.Output, s)
+		}
+		ci.Exit(0)
+		return nil
+	}
+
 	//Check and run subCommands. With level0=SubCommand(subCommandLevel0)
-	//In some cases, we want to just get the args and ignore completely the commands
-	subCommands, args := c.findSubCommand(ci.InputArgs, c.IgnoreCommands)
-	c.Logger.DebugContext(ctx, "findSubCommand and flags", slog.Any("subCommands", subCommands), slog.Any("args", args))
-	paramsImpl, initFlags, finalValues, cb, err := c.initParams(ctx, []subcommand.SubCommand{subCommandLevel0}, subCommands, c)
+	cl, err := c.parseCommandLine(ctx, ci.InputArgs)
 	if err != nil {
 		return c.usageWhenConfigError(err)
 	}
-
-	//using the std go flag lib is a bit annoying. But std are good.
-	fs := flag.NewFlagSet("", flag.ContinueOnError)
-	fs.SetOutput(io.Discard)
-	for _, initFlag := range initFlags {
-		initFlag(fs)
+	if cl.help {
+		if err := c.helpRenderer(ci.Output).RenderUsage(ci.Output, cl.usageModel(c)); err != nil {
+			return err
+		}
+		ci.Exit(0)
+		return nil
 	}
-	if err := fs.Parse(args); err != nil {
-		c.Logger.WarnContext(ctx, "fail parse flags", slog.String("err", err.Error()), slog.Bool("IgnoreFlagProvidedNotDefined", c.IgnoreFlagProvidedNotDefined))
-		if !(c.IgnoreFlagProvidedNotDefined && strings.HasPrefix(err.Error(), errFlagProvidedNotDefined)) {
-			return c.usageWhenConfigError(errors.ConfigError{SubCommands: subCommands, Err: errors.FlagUnknownError{Err: err}})
+	if errs := c.checkRuleNames([]subcommand.SubCommand{subCommandLevel0}, nil); len(errs) > 0 {
+		return c.usageWhenConfigError(errors.ConfigAggregatedError{Errs: errs})
+	}
+	subCommands := cl.subCommands
+	c.Logger.DebugContext(ctx, "parseCommandLine", slog.Any("subCommands", subCommands), slog.Any("args", cl.args))
+	c.checkEnvVars(ctx, sortedEnviron())
+	in := initInputs{flags: cl.flags}
+	if in.configFile, err = c.readConfigFiles(); err != nil {
+		return c.usageWhenConfigError(err)
+	}
+	if in.dotEnv, err = c.readDotEnvFiles(); err != nil {
+		return c.usageWhenConfigError(err)
+	}
+	paramsImpl, finalValues, cmd, err := c.initParams(ctx, c, []subcommand.SubCommand{subCommandLevel0}, subCommands, c, source.PriorityDefault(), in)
+	if err != nil {
+		return c.usageWhenConfigError(err)
+	}
+	for _, pi := range paramsImpl {
+		pi.flagStyle = c.FlagStyle
+	}
+
+	//After an ignored unknown flag, the positional args are unknown and not checked. Same for the ignored commands.
+	argValues := ArgValues{}
+	if !cl.argsUnknown && !(c.IgnoreCommands && len(cmd.Args) == 0) {
+		if argValues, err = cmd.parseArgs(cl.args); err != nil {
+			return c.usageWhenConfigError(errors.ConfigError{SubCommands: append([]subcommand.SubCommand{subCommandLevel0}, subCommands...), Err: err})
 		}
 	}
 
@@ -60,27 +87,21 @@ func (c *Manager) Init(ctx context.Context, opts ...configInitOptions) error {
 	}
 	var aggErr errors.ConfigAggregatedError
 
-	//Check exclusive params
-	for _, p := range paramsImpl {
-		for _, excl := range p.Exclusive {
-			p2, ok := paramsImpl[excl]
-			if !ok {
-				//it should exist! check done at creation.
-				continue
-			}
-			if p2.hasValue {
-				aggErr.Errs = append(aggErr.Errs, errors.ParamConfigError{ParamName: p.Name, Err: fmt.Errorf("exclusive with param:%q", p2.Name)})
-			}
-		}
-	}
+	//Keep the state for Reload()
+	c.paramsImpl = paramsImpl
+	c.subCommandsInit = append([]subcommand.SubCommand{subCommandLevel0}, subCommands...)
+	c.managersInit = cl.managers
+
+	//Check the rules between params, like exclusive params. All the violations are returned.
+	aggErr.Errs = append(aggErr.Errs, c.checkRules(cl.managers)...)
 
-	//Start sync. Skip if not defined or if has EnvVar or Flag override. (Loader is lower priority)
+	//Start sync. Skip if not defined or if a higher priority source has a value. (Unless Loader.AlwaysSync)
 	for _, p := range paramsImpl {
-		if p.Loader.Getter == nil || p.Loader.SynchroFrequency == 0 || p.hasEnvVarOrFlag {
+		if p.Loader.Getter == nil || p.Loader.SynchroFrequency == 0 || !p.readsLoader() {
 			c.Logger.DebugContext(ctx, "Loader will not be synchronizing", slog.String("param", p.Name.String()))
 			continue
 		}
-		if err := c.startSync(ctx, p, c.LoadErrorHandler, append([]subcommand.SubCommand{subCommandLevel0}, subCommands...)); err != nil {
+		if err := c.startSync(ctx, p, c.LoadErrorHandler); err != nil {
 			aggErr.Errs = append(aggErr.Errs, err)
 		}
 	}
@@ -89,72 +110,66 @@ func (c *Manager) Init(ctx context.Context, opts ...configInitOptions) error {
 		return c.usageWhenConfigError(aggErr)
 	}
 
-	if cb != nil {
-		if err := cb(); err != nil {
-			return c.usageWhenConfigError(fmt.Errorf("fail command callback, %s", err))
+	if cmd.Callback != nil {
+		if err := cmd.Callback(); err != nil {
+			return c.usageWhenConfigError(fmt.Errorf("fail command callback, %w", err))
 		}
 	}
-	return nil
+	return run(ctx, cl.managers, Invocation{SubCommands: append([]subcommand.SubCommand{}, c.subCommandsInit...), Args: argValues, Snapshot: c.Snapshot()})
 }
 
 func (c *Manager) initParams(
 	ctx context.Context,
+	root *Manager,
 	subCommandsParent []subcommand.SubCommand,
 	subCommandsRemaining []subcommand.SubCommand,
 	subCmdConfig *Manager,
+	sourcePriorityParent []source.Source,
+	in initInputs,
 ) (
 	_ map[paramname.ParamName]*paramImpl,
-	initFlags []func(*flag.FlagSet),
 	finalValues []func() (_ error),
-	callback func() error,
+	cmd *Manager,
 	_ error,
 ) {
 	paramsImpl := map[paramname.ParamName]*paramImpl{}
+	//SubCommands inherit the priority, unless redefined.
+	sourcePriority := subCmdConfig.inheritPriority(sourcePriorityParent)
 	for _, p := range subCmdConfig.Params {
 		if p.IsSubCommandLocal && len(subCommandsRemaining) > 0 {
 			continue
 		}
-		pi := &paramImpl{Param: p, hasEnvVarOrFlag: true}
+		pi := &paramImpl{Param: p, priority: paramPriority(p, sourcePriority), naming: root.naming(subCommandsParent), loadLock: lock.New()}
 		paramsImpl[p.Name] = pi
-		initFlag, setValue, err := pi.init(ctx, c.Logger, c.lock, subCommandsParent)
+		setValue, err := pi.init(ctx, c.Logger, c.lock, subCommandsParent, in)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, err
 		}
-		initFlags = append(initFlags, initFlag)
 		finalValues = append(finalValues, setValue)
 	}
 	if len(subCommandsRemaining) == 0 {
-		return paramsImpl, initFlags, finalValues, subCmdConfig.Callback, nil
+		return paramsImpl, finalValues, subCmdConfig, nil
 	}
 
 	//recursive 1 level down
 	subCommandsParent = append(subCommandsParent, subCommandsRemaining[0])
-	subSubCmdConfig, ok := subCmdConfig.SubCommands[subCommandsRemaining[0]]
-	if !ok {
-		expected := []subcommand.SubCommand{}
-		for k := range subCmdConfig.SubCommands {
-			expected = append(expected, k)
-		}
-		return nil, nil, nil, nil, errors.ConfigError{
-			SubCommands: subCommandsParent,
-			Err:         fmt.Errorf("undefined command. Declared: %v", expected)}
-	}
-	pis, fss, fvs, cb, err := subCmdConfig.initParams(ctx, subCommandsParent, subCommandsRemaining[1:], subSubCmdConfig)
+	//The names are resolved by parseCommandLine(), including the aliases. An unknown command is already an error.
+	subSubCmdConfig := subCmdConfig.SubCommands[subCommandsRemaining[0]]
+	pis, fvs, cmd, err := subCmdConfig.initParams(ctx, root, subCommandsParent, subCommandsRemaining[1:], subSubCmdConfig, sourcePriority, in)
 	if err != nil {
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, err
 	}
 	for k, v := range paramsImpl {
 		pis[k] = v
 	}
 
-	return pis, append(fss, initFlags...), append(fvs, finalValues...), cb, nil
+	return pis, append(fvs, finalValues...), cmd, nil
 }
 
 func (c *Manager) startSync(
 	ctx context.Context,
 	p *paramImpl,
 	syncError func(_ paramname.ParamName, consecutiveErrNb int, _ error),
-	subCommands []subcommand.SubCommand,
 ) error {
 	if p.Loader.Getter == nil {
 		return nil
@@ -163,9 +178,6 @@ func (c *Manager) startSync(
 		return errors.ParamConfigError{ParamName: p.Name, Err: fmt.Errorf("expect SynchroFrequency > 0")}
 	}
 	go func() {
-		//Value is another cache, hopefully only for values with a loader and no local config
-		var value string
-
 		ticker := time.NewTicker(p.Loader.SynchroFrequency)
 		defer ticker.Stop()
 		consecutiveErrNb := 0
@@ -174,40 +186,37 @@ func (c *Manager) startSync(
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				valueNew, err := p.load(ctx, c.lock, value, subCommands)
-				if err != nil {
+				if err := c.loadAndNotify(ctx, p); err != nil {
 					c.Logger.DebugContext(ctx, "fail Loader", slog.String("param", p.Name.String()), slog.String("err", err.Error()), slog.Int("consecutiveErrNb", consecutiveErrNb))
 					consecutiveErrNb++
 					syncError(p.Name, consecutiveErrNb, err)
+					continue
 				}
-				if valueNew != value {
-					value = valueNew
-					if p.Loader.OnChanged != nil {
-						p.Loader.OnChanged()
-					}
-				}
+				consecutiveErrNb = 0
 			}
 		}
 	}()
 	return nil
 }
 
-func (c *Manager) findSubCommand(args []string, ignoreCommands bool) (_ []subcommand.SubCommand, argsWithoutCommand []string) {
-	res := []subcommand.SubCommand{}
-	hasSubCommand := false
-	for i := 0; i < len(args); i++ {
-		if !strings.HasPrefix(args[i], "-") {
-			if ignoreCommands {
-				continue
-			}
-			hasSubCommand = true
-			res = append(res, subcommand.SubCommand(args[i]))
-		} else {
-			return res, args[i:]
-		}
+// loadAndNotify calls the Loader and triggers OnChanged when the value is different.
+//
+// Skipped when a higher priority source has a value, like an env var or a flag. (Unless Loader.AlwaysSync)
+func (c *Manager) loadAndNotify(ctx context.Context, p *paramImpl) error {
+	if err := p.loadLock.LockWithContext(ctx); err != nil {
+		return err
 	}
-	if hasSubCommand {
-		return res, []string{}
+	defer p.loadLock.Unlock()
+
+	if !p.readsLoader() {
+		return nil
 	}
-	return res, args
+	changed, err := p.load(ctx, c.lock, p.subCommands)
+	if err != nil {
+		return err
+	}
+	if changed && p.Loader.OnChanged != nil {
+		p.Loader.OnChanged()
+	}
+	return nil
 }
@@ -1,64 +1,129 @@
 package config
 
 import (
+	"bytes"
 	"context"
+	stderrors "errors"
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
 
+	"log/slog"
+
+	"github.com/vincentkerdraon/configo/config/errors"
 	"github.com/vincentkerdraon/configo/config/param"
 	"github.com/vincentkerdraon/configo/config/param/paramname"
+	"github.com/vincentkerdraon/configo/config/param/source"
 	"github.com/vincentkerdraon/configo/config/subcommand"
 )
 
-func TestConfig_findSubCommand(t *testing.T) {
+func TestManager_parseCommandLine(t *testing.T) {
+	newManager := func(ignoreCommands bool) *Manager {
+		pVerbose, _ := param.NewBool("Verbose", func(b bool) error { return nil }, param.WithFlag(param.WithShortName('v')))
+		pRootLocal, _ := param.New("RootLocal", func(s string) error { return nil }, param.WithIsSubCommandLocal(true))
+		pRegion, _ := param.New("Region", func(s string) error { return nil })
+		cDeploy, _ := New(WithParams(pRegion), WithArg("env", WithArgArity(0, 1)))
+		cStatus, _ := New()
+		c, _ := New(
+			WithParams(pVerbose, pRootLocal),
+			WithSubCommand("deploy", cDeploy),
+			WithSubCommand("status", cStatus),
+			WithIgnoreCommands(ignoreCommands),
+		)
+		return c
+	}
 	tests := []struct {
-		name                   string
-		args                   []string
-		ignoreCommands         bool
-		wantSubCommands        []subcommand.SubCommand
-		wantArgsWithoutCommand []string
+		name            string
+		args            []string
+		ignoreCommands  bool
+		wantSubCommands []subcommand.SubCommand
+		wantFlags       []string
+		wantArgs        []string
+		wantErr         bool
 	}{
 		{
-			name:                   "ok",
-			args:                   []string{"sc1", "sc2", "-p1", "-p2"},
-			wantSubCommands:        []subcommand.SubCommand{"sc1", "sc2"},
-			wantArgsWithoutCommand: []string{"-p1", "-p2"},
+			name:            "parent flag before the subcommand",
+			args:            []string{"-v", "deploy", "-Region=x", "prod"},
+			wantSubCommands: []subcommand.SubCommand{"deploy"},
+			wantFlags:       []string{"Region=x", "Verbose=true"},
+			wantArgs:        []string{"prod"},
+		},
+		{
+			name:            "parent flag after the subcommand",
+			args:            []string{"deploy", "-Verbose", "prod"},
+			wantSubCommands: []subcommand.SubCommand{"deploy"},
+			wantFlags:       []string{"Verbose=true"},
+			wantArgs:        []string{"prod"},
+		},
+		{
+			name:    "subcommand flag before the subcommand",
+			args:    []string{"-Region=x", "deploy"},
+			wantErr: true,
+		},
+		{
+			name:    "local param with a subcommand",
+			args:    []string{"-RootLocal=a", "status"},
+			wantErr: true,
+		},
+		{
+			name:            "local param without subcommand",
+			args:            []string{"-RootLocal=a"},
+			wantSubCommands: []subcommand.SubCommand{},
+			wantFlags:       []string{"RootLocal=a"},
+			wantArgs:        []string{},
 		},
 		{
-			name:                   "empty",
-			args:                   []string{},
-			wantSubCommands:        []subcommand.SubCommand{},
-			wantArgsWithoutCommand: []string{},
+			name:    "undefined command",
+			args:    []string{"-v", "unknown"},
+			wantErr: true,
 		},
 		{
-			name:                   "no sub cmd",
-			args:                   []string{"-p1", "-p2"},
-			wantSubCommands:        []subcommand.SubCommand{},
-			wantArgsWithoutCommand: []string{"-p1", "-p2"},
+			name:    "no positional args declared",
+			args:    []string{"status", "extra"},
+			wantErr: true,
 		},
 		{
-			name:                   "only sub cmd",
-			args:                   []string{"sc1", "sc2"},
-			wantSubCommands:        []subcommand.SubCommand{"sc1", "sc2"},
-			wantArgsWithoutCommand: []string{},
+			name:            "terminator",
+			args:            []string{"deploy", "--", "-v"},
+			wantSubCommands: []subcommand.SubCommand{"deploy"},
+			wantFlags:       []string{},
+			wantArgs:        []string{"-v"},
 		},
 		{
-			name:                   "ignore sub cmd",
-			args:                   []string{"sc1", "sc2", "-p1", "-p2"},
-			ignoreCommands:         true,
-			wantSubCommands:        []subcommand.SubCommand{},
-			wantArgsWithoutCommand: []string{"-p1", "-p2"},
+			name:            "ignore sub cmd",
+			args:            []string{"sc1", "-v", "sc2"},
+			ignoreCommands:  true,
+			wantSubCommands: []subcommand.SubCommand{},
+			wantFlags:       []string{"Verbose=true"},
+			wantArgs:        []string{},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c, _ := New()
-			gotSubCommands, gotArgsWithoutCommand := c.findSubCommand(tt.args, tt.ignoreCommands)
-			if fmt.Sprintf("%+v", gotSubCommands) != fmt.Sprintf("%+v", tt.wantSubCommands) {
-				t.Errorf("Config.findSubCommand() SubCommands\ngot =%+v\nwant=%+v", gotSubCommands, tt.wantSubCommands)
+			c := newManager(tt.ignoreCommands)
+			got, err := c.parseCommandLine(context.Background(), tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Manager.parseCommandLine() error = %v, wantErr %v", err, tt.wantErr)
 			}
-			if fmt.Sprintf("%+v", gotArgsWithoutCommand) != fmt.Sprintf("%+v", tt.wantArgsWithoutCommand) {
-				t.Errorf("Config.findSubCommand() ArgsWithoutCommand\ngot =%+v\nwant=%+v", gotArgsWithoutCommand, tt.wantArgsWithoutCommand)
+			if err != nil {
+				return
+			}
+			gotFlags := []string{}
+			for k, fv := range got.flags {
+				if fv.isSet {
+					gotFlags = append(gotFlags, k.name.String()+"="+fv.value)
+				}
+			}
+			sort.Strings(gotFlags)
+			if fmt.Sprintf("%q", got.subCommands) != fmt.Sprintf("%q", tt.wantSubCommands) {
+				t.Errorf("Manager.parseCommandLine() SubCommands\ngot =%q\nwant=%q", got.subCommands, tt.wantSubCommands)
+			}
+			if fmt.Sprintf("%q", gotFlags) != fmt.Sprintf("%q", tt.wantFlags) {
+				t.Errorf("Manager.parseCommandLine() flags\ngot =%q\nwant=%q", gotFlags, tt.wantFlags)
+			}
+			if fmt.Sprintf("%q", got.args) != fmt.Sprintf("%q", tt.wantArgs) {
+				t.Errorf("Manager.parseCommandLine() args\ngot =%q\nwant=%q", got.args, tt.wantArgs)
 			}
 		})
 	}
@@ -126,7 +191,7 @@ func TestConfig_initParams(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c, _ := New()
-			got, _, _, _, err := c.initParams(context.Background(), []subcommand.SubCommand{subCommandLevel0}, tt.args.subCmd, tt.args.subCmdConfig)
+			got, _, _, err := c.initParams(context.Background(), c, []subcommand.SubCommand{subCommandLevel0}, tt.args.subCmd, tt.args.subCmdConfig, source.PriorityDefault(), initInputs{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Config.initParams() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -143,3 +208,57 @@ func TestConfig_initParams(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_subCommand_aliasHiddenDeprecated(t *testing.T) {
+	var logs bytes.Buffer
+	cRemove, _ := New(WithAliases("rm"))
+	cDebug, _ := New(WithHidden(true))
+	cDelete, _ := New(WithDeprecated(`use "remove"`))
+	c, err := New(
+		WithSubCommand("remove", cRemove),
+		WithSubCommand("debug", cDebug),
+		WithSubCommand("delete", cDelete),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := New(WithSubCommand("remove", cRemove), WithSubCommand("rm", cDebug)); err == nil {
+		t.Errorf("New() expect error when a name is already an alias")
+	}
+
+	got, err := c.parseCommandLine(context.Background(), []string{"rm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprintf("%q", got.subCommands) != `["remove"]` {
+		t.Errorf("parseCommandLine() alias\ngot =%q\nwant=%q", got.subCommands, []string{"remove"})
+	}
+
+	if _, err := c.parseCommandLine(context.Background(), []string{"delete"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs.String(), `msg="deprecated command" command=delete replacement="use \"remove\""`) {
+		t.Errorf("parseCommandLine() expect deprecated warning, got =%s", logs.String())
+	}
+
+	if _, err := c.parseCommandLine(context.Background(), []string{"debug"}); err != nil {
+		t.Errorf("parseCommandLine() hidden command must work, got =%s", err)
+	}
+	usage := c.Usage(0)
+	if strings.Contains(usage, "debug") || !strings.Contains(usage, "Command: remove (aliases: rm)") || !strings.Contains(usage, `Command: delete (deprecated, use "remove")`) {
+		t.Errorf("Usage()\ngot =%s", usage)
+	}
+	if gotComplete := c.complete([]string{""}); fmt.Sprintf("%q", gotComplete) != `["delete" "remove"]` {
+		t.Errorf("complete()\ngot =%q", gotComplete)
+	}
+
+	if err := c.Init(context.Background(), WithInputArgs([]string{"rm"})); err != nil {
+		t.Errorf("Init() alias\ngot =%v", err)
+	}
+	err = c.Init(context.Background(), WithInputArgs([]string{"unknown"}))
+	if !stderrors.As(err, &errors.SubCommandUnknownError{}) {
+		t.Errorf("Init() unknown command\ngot =%#v\nwant=SubCommandUnknownError", err)
+	}
+}
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/subcommand"
+)
+
+// jsonSchemaVersion is the JSON schema draft used.
+const jsonSchemaVersion = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchemaDurationPattern matches a Go duration, like `1h30m`.
+const jsonSchemaDurationPattern = `^-?([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+type jsonSchemaObject map[string]interface{}
+
+// JSONSchema describes all the params as a JSON schema document. For example to validate a config file.
+//
+// The keys are the param names. Dotted names (like with a prefix `db.Host`) and subcommands are nested objects.
+// The type is inferred from param.Type, when known.
+func (c *Manager) JSONSchema() ([]byte, error) {
+	schema, err := c.jsonSchema(nil)
+	if err != nil {
+		return nil, err
+	}
+	schema["$schema"] = jsonSchemaVersion
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// jsonSchema of this Manager, at this subcommand path.
+//
+// A key can't be both a param and a nested object (`DB` and `DB.Host`), or both a param and a subcommand.
+func (c *Manager) jsonSchema(path []subcommand.SubCommand) (jsonSchemaObject, error) {
+	res := jsonSchemaObject{
+		"type":       "object",
+		"properties": jsonSchemaObject{},
+	}
+	if c.Description != "" {
+		res["description"] = c.Description
+	}
+	for _, p := range c.sortedParams() {
+		parent := res
+		keys := strings.Split(p.Name.String(), ".")
+		for i, k := range keys[:len(keys)-1] {
+			properties := parent["properties"].(jsonSchemaObject)
+			child, ok := properties[k].(jsonSchemaObject)
+			if !ok {
+				child = jsonSchemaObject{"type": "object", "properties": jsonSchemaObject{}}
+				properties[k] = child
+			} else if _, isObject := child["properties"]; !isObject {
+				return nil, errors.ParamConfigError{SubCommands: path, ParamName: p.Name, Err: fmt.Errorf("json schema, key:%q is both a param and an object", strings.Join(keys[:i+1], "."))}
+			}
+			parent = child
+		}
+		leaf := keys[len(keys)-1]
+		properties := parent["properties"].(jsonSchemaObject)
+		if _, f := properties[leaf]; f {
+			return nil, errors.ParamConfigError{SubCommands: path, ParamName: p.Name, Err: fmt.Errorf("json schema, key:%q is both a param and an object", p.Name)}
+		}
+		properties[leaf] = jsonSchemaParam(p)
+		if p.IsMandatory {
+			required, _ := parent["required"].([]string)
+			parent["required"] = append(required, leaf)
+		}
+	}
+	for _, subCmd := range c.sortedSubCommands() {
+		subPath := append(append([]subcommand.SubCommand{}, path...), subCmd)
+		properties := res["properties"].(jsonSchemaObject)
+		if _, f := properties[subCmd.String()]; f {
+			return nil, errors.ConfigError{SubCommands: subPath, Err: fmt.Errorf("json schema, key:%q is both a param and a subcommand", subCmd)}
+		}
+		sub, err := c.SubCommands[subCmd].jsonSchema(subPath)
+		if err != nil {
+			return nil, err
+		}
+		properties[subCmd.String()] = sub
+	}
+	return res, nil
+}
+
+func jsonSchemaParam(p param.Param) jsonSchemaObject {
+	res := jsonSchemaObject{}
+	t := p.Type
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if sep := p.ListSeparator(); sep != "" && t.Kind() == reflect.Slice {
+		return jsonSchemaArray(p, t.Elem(), sep)
+	}
+	if sep := p.ListSeparator(); sep != "" && t.Kind() == reflect.Map {
+		return jsonSchemaMap(p, t.Elem(), sep)
+	}
+	typeName := jsonSchemaType(t)
+	if typeName != "" {
+		res["type"] = typeName
+	}
+	if t == reflect.TypeOf(time.Duration(0)) {
+		res["pattern"] = jsonSchemaDurationPattern
+	}
+	if p.Desc != "" {
+		res["description"] = p.Desc
+	}
+	//A sensitive default would be published with the schema.
+	if p.Default != "" && !p.IsSensitive {
+		res["default"] = jsonSchemaValue(typeName, p.Default)
+	}
+	if len(p.EnumValues) > 0 {
+		enum := []interface{}{}
+		for _, v := range p.EnumValues {
+			enum = append(enum, jsonSchemaValue(typeName, v))
+		}
+		res["enum"] = enum
+	}
+	if len(p.Examples) > 0 {
+		examples := []interface{}{}
+		for _, v := range p.Examples {
+			examples = append(examples, jsonSchemaValue(typeName, v))
+		}
+		res["examples"] = examples
+	}
+	return res
+}
+
+// jsonSchemaArray is a slice param, an array in the config files.
+func jsonSchemaArray(p param.Param, elem reflect.Type, sep string) jsonSchemaObject {
+	items := jsonSchemaParam(param.Param{Type: elem, EnumValues: p.EnumValues})
+	itemType, _ := items["type"].(string)
+	res := jsonSchemaObject{"type": "array", "items": items}
+	if p.Desc != "" {
+		res["description"] = p.Desc
+	}
+	values := func(s string) []interface{} {
+		res := []interface{}{}
+		for _, v := range param.SplitList(s, sep) {
+			res = append(res, jsonSchemaValue(itemType, v))
+		}
+		return res
+	}
+	if p.Default != "" && !p.IsSensitive {
+		res["default"] = values(p.Default)
+	}
+	if len(p.Examples) > 0 {
+		examples := []interface{}{}
+		for _, v := range p.Examples {
+			examples = append(examples, values(v))
+		}
+		res["examples"] = examples
+	}
+	return res
+}
+
+// jsonSchemaMap is a map param. The keys of the object are the map keys, like in the config files.
+func jsonSchemaMap(p param.Param, elem reflect.Type, sep string) jsonSchemaObject {
+	items := jsonSchemaParam(param.Param{Type: elem, EnumValues: p.EnumValues})
+	itemType, _ := items["type"].(string)
+	res := jsonSchemaObject{"type": "object", "additionalProperties": items}
+	if p.Desc != "" {
+		res["description"] = p.Desc
+	}
+	values := func(s string) jsonSchemaObject {
+		res := jsonSchemaObject{}
+		for _, item := range param.SplitList(s, sep) {
+			if k, v, err := param.SplitKeyValue(item); err == nil {
+				res[k] = jsonSchemaValue(itemType, v)
+			}
+		}
+		return res
+	}
+	if p.Default != "" && !p.IsSensitive {
+		res["default"] = values(p.Default)
+	}
+	if len(p.Examples) > 0 {
+		examples := []interface{}{}
+		for _, v := range p.Examples {
+			examples = append(examples, values(v))
+		}
+		res["examples"] = examples
+	}
+	return res
+}
+
+// jsonSchemaType is the JSON type for a Go type. Empty when unknown.
+func jsonSchemaType(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	}
+	return ""
+}
+
+// jsonSchemaValue converts a raw value to the JSON type. Kept as string when not possible.
+func jsonSchemaValue(typeName string, s string) interface{} {
+	switch typeName {
+	case "boolean":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case "integer":
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return u
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/paramname"
+)
+
+func TestManager_JSONSchema(t *testing.T) {
+	type DB struct {
+		Host    string `mandatory:"true" desc:"DB host" examples:"localhost"`
+		Port    int    `default:"5432"`
+		Timeout time.Duration
+	}
+	pVerbose, err := param.NewBool("Verbose", func(bool) error { return nil }, param.WithDefault("false"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pRegion, err := param.NewString("Region", func(string) error { return nil }, param.WithEnumValues("ca", "us"), param.WithIsMandatory(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pRaw, err := param.New("Raw", func(string) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	cDeploy, err := New(WithParams(pRegion), WithDescription("Deploy the app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParamsFromStructTag(&DB{}, "db."), WithParams(pVerbose, pRaw), WithSubCommand("deploy", cDeploy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "Raw": {},
+    "Verbose": {
+      "default": false,
+      "type": "boolean"
+    },
+    "db": {
+      "properties": {
+        "Host": {
+          "description": "DB host",
+          "examples": [
+            "localhost"
+          ],
+          "type": "string"
+        },
+        "Port": {
+          "default": 5432,
+          "type": "integer"
+        },
+        "Timeout": {
+          "pattern": "^-?([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$",
+          "type": "string"
+        }
+      },
+      "required": [
+        "Host"
+      ],
+      "type": "object"
+    },
+    "deploy": {
+      "description": "Deploy the app",
+      "properties": {
+        "Region": {
+          "enum": [
+            "ca",
+            "us"
+          ],
+          "type": "string"
+        }
+      },
+      "required": [
+        "Region"
+      ],
+      "type": "object"
+    }
+  },
+  "type": "object"
+}`
+	if string(got) != want {
+		t.Errorf("JSONSchema\ngot =%s\nwant=%s", got, want)
+	}
+}
+
+func TestManager_JSONSchema_slice(t *testing.T) {
+	pPorts, err := param.NewIntSlice("Ports", func([]int) error { return nil }, param.WithDefault("80, 443"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pLabels, err := param.NewStringMap("Labels", func(map[string]string) error { return nil }, param.WithDefault("env=prod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(pPorts, pLabels))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "Labels": {
+      "additionalProperties": {
+        "type": "string"
+      },
+      "default": {
+        "env": "prod"
+      },
+      "type": "object"
+    },
+    "Ports": {
+      "default": [
+        80,
+        443
+      ],
+      "items": {
+        "type": "integer"
+      },
+      "type": "array"
+    }
+  },
+  "type": "object"
+}`
+	if string(got) != want {
+		t.Errorf("Manager.JSONSchema()\ngot =%s\nwant=%s", got, want)
+	}
+}
+
+func TestManager_JSONSchema_collision(t *testing.T) {
+	newParam := func(name paramname.ParamName) *param.Param {
+		p, err := param.NewString(name, func(string) error { return nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	cDeploy, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		opts []configOptionsF
+	}{
+		{name: "param and object", opts: []configOptionsF{WithParams(newParam("DB"), newParam("DB.Host"))}},
+		{name: "object and param, nested", opts: []configOptionsF{WithParams(newParam("A.B.C"), newParam("A.B"))}},
+		{name: "param and subcommand", opts: []configOptionsF{WithParams(newParam("deploy")), WithSubCommand("deploy", cDeploy)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := c.JSONSchema(); err == nil {
+				t.Errorf("Manager.JSONSchema() expect error, got =%s", got)
+			}
+		})
+	}
+}
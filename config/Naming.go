@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/subcommand"
+)
+
+type (
+	// Naming converts a param name into a flag or env var name, see WithEnvVarNaming() and WithFlagNaming().
+	Naming func(name string) string
+
+	// naming is how the flag and env var names are derived for the params of a subcommand.
+	naming struct {
+		flag   Naming
+		envVar Naming
+		// envVarPrefix is the global prefix, plus the subcommands segments. Like `MYAPP_DEPLOY_`
+		envVarPrefix string
+	}
+)
+
+var (
+	// NamingUpperSnake like `READ_TIMEOUT` for `ReadTimeout`, `DB_HOST` for `DB.Host`
+	NamingUpperSnake Naming = param.UpperSnakeCase
+
+	// NamingKebab like `read-timeout` for `ReadTimeout`, `db-host` for `DB.Host`
+	NamingKebab Naming = param.KebabCase
+)
+
+// WithEnvVarNaming converts the param names into env var names, like NamingUpperSnake. Only on the root Manager.
+//
+// The names defined with param.WithEnvVarName() (or the struct tag) are kept.
+// default: the param name
+func WithEnvVarNaming(n Naming) configOptionsF {
+	return func(c *Manager) error {
+		c.EnvVarNaming = n
+		return nil
+	}
+}
+
+// WithFlagNaming converts the param names into flag names, like NamingKebab. Only on the root Manager.
+//
+// The names defined with param.WithFlagName() (or the struct tag) are kept.
+// default: the param name
+func WithFlagNaming(n Naming) configOptionsF {
+	return func(c *Manager) error {
+		c.FlagNaming = n
+		return nil
+	}
+}
+
+// WithEnvPrefix is added in front of all the env var names, like `MYAPP_`. Only on the root Manager.
+//
+// The params of a subcommand also get the subcommand segments: `MYAPP_DEPLOY_REGION` for the param Region in `tool deploy`.
+// Also added to the names defined with param.WithEnvVarName() (or the struct tag).
+// The env vars starting with this prefix but not matching any param are reported, see WithEnvVarCheck().
+func WithEnvPrefix(prefix string) configOptionsF {
+	return func(c *Manager) error {
+		if prefix == "" {
+			return errors.ConfigError{Err: fmt.Errorf("mandatory env var prefix when using the option")}
+		}
+		c.EnvPrefix = prefix
+		if c.EnvVarCheckPrefix == "" {
+			c.EnvVarCheckPrefix = prefix
+		}
+		return nil
+	}
+}
+
+// naming for the params of the subcommand at this path. (Without level 0)
+func (c *Manager) naming(path []subcommand.SubCommand) naming {
+	res := naming{flag: c.FlagNaming, envVar: c.EnvVarNaming, envVarPrefix: c.EnvPrefix}
+	if c.EnvPrefix == "" {
+		return res
+	}
+	for _, subCmd := range path {
+		if subCmd != subCommandLevel0 {
+			res.envVarPrefix += param.UpperSnakeCase(subCmd.String()) + "_"
+		}
+	}
+	return res
+}
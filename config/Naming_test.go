@@ -0,0 +1,85 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vincentkerdraon/configo/config/param"
+)
+
+func TestManager_naming(t *testing.T) {
+	t.Setenv("MYAPP_READ_TIMEOUT", "3s")
+	t.Setenv("MYAPP_DEPLOY_REGION", "eu-west-1")
+	t.Setenv("MYAPP_CUSTOM", "from custom")
+	t.Setenv("READ_TIMEOUT", "9s")
+	t.Setenv("Region", "wrong")
+
+	conf := struct {
+		ReadTimeout time.Duration
+		MaxConns    int
+		Name        string `envVar:"CUSTOM" flag:"the-name"`
+	}{}
+	var region string
+	pRegion, err := param.NewString("Region", func(s string) error { region = s; return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	cDeploy, err := New(WithParams(pRegion))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(
+		WithParamsFromStructTag(&conf, ""),
+		WithSubCommand("deploy", cDeploy),
+		WithEnvVarNaming(NamingUpperSnake),
+		WithFlagNaming(NamingKebab),
+		WithEnvPrefix("MYAPP_"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{"-max-conns=4", "deploy"})); err != nil {
+		t.Fatal(err)
+	}
+	if conf.ReadTimeout != 3*time.Second || conf.MaxConns != 4 || conf.Name != "from custom" || region != "eu-west-1" {
+		t.Errorf("naming\ngot =%+v region=%q\nwant={ReadTimeout:3s MaxConns:4 Name:from custom} region=\"eu-west-1\"", conf, region)
+	}
+
+	usage := c.Usage(0)
+	for _, want := range []string{
+		"Command line flag: -read-timeout",
+		"Command line flag: -the-name",
+		"Environment variable name: MYAPP_READ_TIMEOUT",
+		"Environment variable name: MYAPP_CUSTOM",
+		"Environment variable name: MYAPP_DEPLOY_REGION",
+	} {
+		if !strings.Contains(usage, want) {
+			t.Errorf("usage\ngot =%s\nwant=%q", usage, want)
+		}
+	}
+	if c.EnvVarCheckPrefix != "MYAPP_" {
+		t.Errorf("env var check prefix\ngot =%q\nwant=%q", c.EnvVarCheckPrefix, "MYAPP_")
+	}
+	if _, err := New(WithEnvPrefix("")); err == nil {
+		t.Errorf("expect error for an empty prefix")
+	}
+}
+
+func TestManager_naming_default(t *testing.T) {
+	t.Setenv("ReadTimeout", "3s")
+	conf := struct {
+		ReadTimeout time.Duration
+	}{}
+	c, err := New(WithParamsFromStructTag(&conf, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{})); err != nil {
+		t.Fatal(err)
+	}
+	if conf.ReadTimeout != 3*time.Second {
+		t.Errorf("default naming\ngot =%v\nwant=%v", conf.ReadTimeout, 3*time.Second)
+	}
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"log/slog"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param/paramname"
+	"github.com/vincentkerdraon/configo/config/param/source"
+)
+
+type (
+	// Reload options, see Manager.Reload()
+	Reload struct {
+		//ReadEnvVar to read again the environment variables.
+		//
+		// default: false
+		ReadEnvVar bool
+
+		//ReadConfigFile to read again the config files.
+		//
+		// default: false
+		ReadConfigFile bool
+	}
+
+	configReloadOptions func(r *Reload) error
+)
+
+// WithReloadEnvVar to read again the environment variables (and the .env files) when reloading.
+//
+// A flag value is never overridden. When the env var disappeared, the Loader (or the Default) is used again.
+//
+// default: false
+func WithReloadEnvVar(t bool) configReloadOptions {
+	return func(r *Reload) error {
+		r.ReadEnvVar = t
+		return nil
+	}
+}
+
+// WithReloadConfigFile to read again the config files (see WithConfigFile()) when reloading.
+//
+// default: false
+func WithReloadConfigFile(t bool) configReloadOptions {
+	return func(r *Reload) error {
+		r.ReadConfigFile = t
+		return nil
+	}
+}
+
+// Reload runs again every Loader of the params selected during Init(), without waiting for SynchroFrequency.
+//
+// Must be called after Init().
+// Loader.OnChanged is called when a value changes.
+// The rules between params and WithValidate() are checked again with the new values.
+// Errors for all the params are returned together in a errors.ConfigAggregatedError.
+func (c *Manager) Reload(ctx context.Context, opts ...configReloadOptions) error {
+	r := Reload{}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(&r); err != nil {
+			return err
+		}
+	}
+	if c.paramsImpl == nil {
+		return errors.ConfigError{Err: fmt.Errorf("call Init() before Reload()")}
+	}
+
+	var in initInputs
+	if r.ReadConfigFile {
+		var err error
+		if in.configFile, err = c.readConfigFiles(); err != nil {
+			return err
+		}
+	}
+	if r.ReadEnvVar {
+		var err error
+		if in.dotEnv, err = c.readDotEnvFiles(); err != nil {
+			return err
+		}
+	}
+
+	//Same order every time, easier to read the logs.
+	names := make([]paramname.ParamName, 0, len(c.paramsImpl))
+	for name := range c.paramsImpl {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	var aggErr errors.ConfigAggregatedError
+	for _, name := range names {
+		p := c.paramsImpl[name]
+		var err error
+		if r.ReadConfigFile && p.reads(source.ConfigFile) {
+			val, found := p.configFileValue(in)
+			err = c.reloadSource(ctx, p, source.ConfigFile, val, found)
+		}
+		if err == nil && r.ReadEnvVar && p.EnvVar.Use && p.reads(source.DotEnv) {
+			var val string
+			if val, err = p.loadDotEnv(in); err == nil {
+				err = c.reloadSource(ctx, p, source.DotEnv, val, val != "")
+			}
+		}
+		if err == nil && r.ReadEnvVar && p.EnvVar.Use && p.reads(source.EnvVar) {
+			var val string
+			if val, err = p.loadEnvVar(); err == nil {
+				err = c.reloadSource(ctx, p, source.EnvVar, val, val != "")
+			}
+		}
+		if err == nil {
+			err = c.loadAndNotify(ctx, p)
+		}
+		if err != nil {
+			c.Logger.DebugContext(ctx, "fail Reload", slog.String("param", p.Name.String()), slog.String("err", err.Error()))
+			pce := errors.ParamConfigError{}
+			if !stderrors.As(err, &pce) {
+				err = errors.ParamConfigError{ParamName: p.Name, SubCommands: p.subCommands, Err: err}
+			}
+			aggErr.Errs = append(aggErr.Errs, err)
+		}
+	}
+	//The new values must respect the rules between params too.
+	aggErr.Errs = append(aggErr.Errs, c.checkRules(c.managersInit)...)
+	if aggErr.Errs != nil {
+		return aggErr
+	}
+	return nil
+}
+
+// reloadSource sets the new value read from a source. The Loader is used as fallback when the value is now missing.
+func (c *Manager) reloadSource(ctx context.Context, p *paramImpl, src source.Source, val string, found bool) error {
+	if err := p.loadLock.LockWithContext(ctx); err != nil {
+		return err
+	}
+	defer p.loadLock.Unlock()
+
+	if valPrevious, ok := p.values[src]; val != valPrevious || found != ok {
+		//A new value wins again against Loader.AlwaysSync
+		p.loaderFirst = false
+	}
+	if found {
+		p.values[src] = val
+	} else {
+		delete(p.values, src)
+		if p.readsLoader() && p.Loader.Getter != nil {
+			//Reload() calls the Loader next.
+			return nil
+		}
+	}
+	p.loadedAt = time.Now()
+	changed, err := p.apply(ctx, c.lock, p.subCommands, false)
+	if err != nil {
+		return err
+	}
+	if changed && p.Loader.OnChanged != nil {
+		p.Loader.OnChanged()
+	}
+	return nil
+}
+
+// ReloadOnSIGHUP calls Reload() every time the process receives SIGHUP, until the context is done.
+//
+// Opt-in, nothing listens to signals otherwise.
+// onReload receives the result of each Reload(). When nil, errors are logged.
+func (c *Manager) ReloadOnSIGHUP(ctx context.Context, onReload func(error), opts ...configReloadOptions) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-ch:
+				c.Logger.InfoContext(ctx, "reloading config", slog.String("signal", sig.String()))
+				err := c.Reload(ctx, opts...)
+				if onReload != nil {
+					onReload(err)
+					continue
+				}
+				if err != nil {
+					c.Logger.ErrorContext(ctx, "fail reload config", slog.String("err", err.Error()))
+				}
+			}
+		}
+	}()
+}
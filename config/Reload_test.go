@@ -0,0 +1,158 @@
+package config
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param"
+)
+
+func TestManager_Reload(t *testing.T) {
+	loaderValue := "v1"
+	var loaderErr error
+	var got string
+	changedNb := 0
+	p, err := param.New("p1",
+		func(s string) error { got = s; return nil },
+		param.WithLoader(
+			func(ctx context.Context) (string, error) { return loaderValue, loaderErr },
+			param.WithCallbackOnChanged(func() { changedNb++ }),
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Reload(context.Background()); err == nil {
+		t.Fatal("expect error when Reload() before Init()")
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{})); err != nil {
+		t.Fatal(err)
+	}
+	if got != "v1" {
+		t.Fatalf("Init\ngot =%q\nwant=%q", got, "v1")
+	}
+
+	//same value, no callback
+	if err := c.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if changedNb != 0 {
+		t.Errorf("Reload same value, OnChanged\ngot =%d\nwant=%d", changedNb, 0)
+	}
+
+	loaderValue = "v2"
+	if err := c.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got != "v2" || changedNb != 1 {
+		t.Errorf("Reload new value\ngot =%q (OnChanged %d)\nwant=%q (OnChanged %d)", got, changedNb, "v2", 1)
+	}
+
+	loaderErr = fmt.Errorf("err fetch")
+	err = c.Reload(context.Background())
+	aggErr := errors.ConfigAggregatedError{}
+	if !stderrors.As(err, &aggErr) || len(aggErr.Errs) != 1 {
+		t.Fatalf("Reload error\ngot =%#v\nwant=ConfigAggregatedError with 1 error", err)
+	}
+	if got != "v2" || changedNb != 1 {
+		t.Errorf("Reload error keeps value\ngot =%q (OnChanged %d)\nwant=%q (OnChanged %d)", got, changedNb, "v2", 1)
+	}
+}
+
+func TestManager_Reload_envVar(t *testing.T) {
+	var got string
+	p, err := param.New("P1",
+		func(s string) error { got = s; return nil },
+		param.WithDefault("default"),
+		param.WithEnvVar(param.WithEnvVarName("CONFIGO_TEST_RELOAD_P1")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{})); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CONFIGO_TEST_RELOAD_P1", "fromEnv")
+	if err := c.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got != "default" {
+		t.Errorf("Reload without env var option\ngot =%q\nwant=%q", got, "default")
+	}
+	if err := c.Reload(context.Background(), WithReloadEnvVar(true)); err != nil {
+		t.Fatal(err)
+	}
+	if got != "fromEnv" {
+		t.Errorf("Reload with env var option\ngot =%q\nwant=%q", got, "fromEnv")
+	}
+
+	t.Setenv("CONFIGO_TEST_RELOAD_P1", "")
+	if err := c.Reload(context.Background(), WithReloadEnvVar(true)); err != nil {
+		t.Fatal(err)
+	}
+	if got != "default" {
+		t.Errorf("Reload env var removed\ngot =%q\nwant=%q", got, "default")
+	}
+}
+
+func TestManager_Reload_validation(t *testing.T) {
+	loaderValue := "10"
+	conf := struct {
+		Port int `min:"1" max:"65535"`
+	}{}
+	p, err := param.NewParamFromStructTag(&conf, "Port", nil,
+		param.WithLoader(func(ctx context.Context) (string, error) { return loaderValue, nil }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("Port", "0")
+	err = c.Init(context.Background(), WithInputArgs([]string{}))
+	validationErr := errors.ParamValidationError{}
+	if !stderrors.As(err, &validationErr) {
+		t.Fatalf("Init validation error\ngot =%#v\nwant=ParamValidationError", err)
+	}
+	if conf.Port != 0 {
+		t.Errorf("Init invalid value\ngot =%d\nwant=%d", conf.Port, 0)
+	}
+
+	t.Setenv("Port", "")
+	if err := c.Init(context.Background(), WithInputArgs([]string{})); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Port != 10 {
+		t.Fatalf("Init\ngot =%d\nwant=%d", conf.Port, 10)
+	}
+
+	//A bad value from the loader never reaches the struct.
+	loaderValue = "70000"
+	err = c.Reload(context.Background())
+	aggErr := errors.ConfigAggregatedError{}
+	if !stderrors.As(err, &aggErr) || len(aggErr.Errs) != 1 || !stderrors.As(aggErr.Errs[0], &validationErr) {
+		t.Errorf("Reload validation error\ngot =%#v\nwant=ParamValidationError", err)
+	}
+	if conf.Port != 10 {
+		t.Errorf("Reload invalid value\ngot =%d\nwant=%d", conf.Port, 10)
+	}
+	if want := "Validation: min:1, max:65535"; !strings.Contains(c.Usage(0), want) {
+		t.Errorf("usage\ngot =%s\nwant=%q", c.Usage(0), want)
+	}
+}
@@ -0,0 +1,78 @@
+//go:build linux || darwin
+
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/vincentkerdraon/configo/config/param"
+)
+
+func TestManager_ReloadOnSIGHUP(t *testing.T) {
+	//Keeps the process alive when SIGHUP is not listened by ReloadOnSIGHUP anymore.
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGHUP)
+	defer signal.Stop(guard)
+
+	loaderValue := "v1"
+	var got string
+	p, err := param.New("P",
+		func(s string) error { got = s; return nil },
+		param.WithLoader(func(ctx context.Context) (string, error) { return loaderValue, nil }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{})); err != nil {
+		t.Fatal(err)
+	}
+
+	loaderValue = "v2"
+	reloaded := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.ReloadOnSIGHUP(ctx, func(err error) { reloaded <- err })
+
+	sighup := func() {
+		if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-guard:
+		case <-time.After(time.Second):
+			t.Fatal("SIGHUP not received")
+		}
+	}
+
+	sighup()
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no reload on SIGHUP")
+	}
+	if got != "v2" {
+		t.Errorf("ReloadOnSIGHUP\ngot =%q\nwant=%q", got, "v2")
+	}
+
+	//Stops with the context.
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	sighup()
+	select {
+	case err := <-reloaded:
+		t.Errorf("ReloadOnSIGHUP after cancel\ngot =reload %v\nwant=no reload", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
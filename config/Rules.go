@@ -0,0 +1,195 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param/paramname"
+	"github.com/vincentkerdraon/configo/config/subcommand"
+)
+
+// WithAtLeastOneOf requires a value for at least one of these params, when this Manager is selected.
+// The params can be declared on this Manager or on a parent, checked by Init().
+//
+// Can be called multiple times.
+func WithAtLeastOneOf(params ...paramname.ParamName) configOptionsF {
+	return func(c *Manager) error {
+		if len(params) < 2 {
+			return errors.ConfigError{Err: fmt.Errorf("expect at least 2 params in the group, got:%v", params)}
+		}
+		c.AtLeastOneOf = append(c.AtLeastOneOf, params)
+		return nil
+	}
+}
+
+// WithExactlyOneOf requires a value for exactly one of these params, when this Manager is selected.
+// The params can be declared on this Manager or on a parent, checked by Init().
+//
+// Can be called multiple times.
+func WithExactlyOneOf(params ...paramname.ParamName) configOptionsF {
+	return func(c *Manager) error {
+		if len(params) < 2 {
+			return errors.ConfigError{Err: fmt.Errorf("expect at least 2 params in the group, got:%v", params)}
+		}
+		c.ExactlyOneOf = append(c.ExactlyOneOf, params)
+		return nil
+	}
+}
+
+// WithValidate is called during Init() after every param is set, with the effective configuration. Also after Reload().
+// For the rules between params not covered by the options. Called when this Manager is selected.
+func WithValidate(f func(s Snapshot) error) configOptionsF {
+	return func(c *Manager) error {
+		c.Validate = f
+		return nil
+	}
+}
+
+// checkRules returns all the violations of the rules between params. Called after every param is set, also by Reload().
+//
+// The managers are the selected levels, starting with the root Manager.
+func (c *Manager) checkRules(managers []*Manager) []error {
+	res := []error{}
+	names := make([]paramname.ParamName, 0, len(c.paramsImpl))
+	for name := range c.paramsImpl {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	for _, name := range names {
+		p := c.paramsImpl[name]
+		_, hasValue := p.current()
+		for _, excl := range p.Exclusive {
+			p2, ok := c.paramsImpl[excl]
+			if !ok {
+				//it should exist! check done by checkRuleNames().
+				continue
+			}
+			if _, hasValue2 := p2.current(); hasValue && hasValue2 {
+				res = append(res, errors.ParamConfigError{SubCommands: p.subCommands, ParamName: p.Name, Err: fmt.Errorf("exclusive with param:%q", p2.Name)})
+			}
+		}
+		for _, req := range p.Requires {
+			p2, ok := c.paramsImpl[req]
+			if !ok {
+				continue
+			}
+			if _, hasValue2 := p2.current(); hasValue && !hasValue2 {
+				res = append(res, errors.ParamConfigError{SubCommands: p.subCommands, ParamName: p.Name, Err: fmt.Errorf("requires param:%q", p2.Name)})
+			}
+		}
+		for _, cond := range p.RequiredIf {
+			p2, ok := c.paramsImpl[cond.Param]
+			if !ok {
+				continue
+			}
+			if value2, _ := p2.current(); value2 == cond.Value && !hasValue {
+				res = append(res, errors.ParamConfigError{SubCommands: p.subCommands, ParamName: p.Name, Err: fmt.Errorf("%w when param:%q is %q", errors.ErrMandatoryValue, p2.Name, cond.Value)})
+				break
+			}
+		}
+	}
+
+	for i, m := range managers {
+		subCommands := c.subCommandsInit[:i+1]
+		for _, group := range m.AtLeastOneOf {
+			if withValue := c.paramsWithValue(group); len(withValue) == 0 {
+				res = append(res, errors.ConfigError{SubCommands: subCommands, Err: fmt.Errorf("expect a value for at least one of params:%v", group)})
+			}
+		}
+		for _, group := range m.ExactlyOneOf {
+			if withValue := c.paramsWithValue(group); len(withValue) != 1 {
+				res = append(res, errors.ConfigError{SubCommands: subCommands, Err: fmt.Errorf("expect a value for exactly one of params:%v, got:%v", group, withValue)})
+			}
+		}
+	}
+
+	snapshot := c.Snapshot()
+	for i, m := range managers {
+		if m.Validate == nil {
+			continue
+		}
+		if err := m.Validate(snapshot); err != nil {
+			res = append(res, errors.ConfigError{SubCommands: c.subCommandsInit[:i+1], Err: err})
+		}
+	}
+	return res
+}
+
+// checkRuleNames returns the params used in a rule but not declared, on this Manager and all the subcommands.
+//
+// declared are the params of the parents, also available in this Manager.
+func (c *Manager) checkRuleNames(path []subcommand.SubCommand, declared map[paramname.ParamName]bool) []error {
+	res := []error{}
+	own := map[paramname.ParamName]bool{}
+	for name := range declared {
+		own[name] = true
+	}
+	for _, p := range c.Params {
+		own[p.Name] = true
+	}
+	check := func(name paramname.ParamName, rule string, names ...paramname.ParamName) {
+		for _, n := range names {
+			if !own[n] {
+				res = append(res, errors.ParamConfigError{SubCommands: path, ParamName: name, Err: fmt.Errorf("unknown param:%q in %s", n, rule)})
+			}
+		}
+	}
+	for _, p := range c.Params {
+		check(p.Name, "exclusive", p.Exclusive...)
+		check(p.Name, "requires", p.Requires...)
+		for _, cond := range p.RequiredIf {
+			check(p.Name, "required if", cond.Param)
+		}
+	}
+	checkGroup := func(rule string, group []paramname.ParamName) {
+		for _, n := range group {
+			if !own[n] {
+				res = append(res, errors.ParamConfigError{SubCommands: path, ParamName: n, Err: fmt.Errorf("unknown param in %s:%v", rule, group)})
+			}
+		}
+	}
+	for _, group := range c.AtLeastOneOf {
+		checkGroup("at least one of", group)
+	}
+	for _, group := range c.ExactlyOneOf {
+		checkGroup("exactly one of", group)
+	}
+
+	//A local param is not available in the subcommands.
+	inherited := map[paramname.ParamName]bool{}
+	for name := range declared {
+		inherited[name] = true
+	}
+	for _, p := range c.Params {
+		if !p.IsSubCommandLocal {
+			inherited[p.Name] = true
+		}
+	}
+	for _, sc := range c.subCommandsOrder {
+		subPath := append(append([]subcommand.SubCommand{}, path...), sc)
+		res = append(res, c.SubCommands[sc].checkRuleNames(subPath, inherited)...)
+	}
+	return res
+}
+
+// paramsWithValue are the params of the group having a value.
+func (c *Manager) paramsWithValue(group []paramname.ParamName) []paramname.ParamName {
+	res := []paramname.ParamName{}
+	for _, name := range group {
+		if p, ok := c.paramsImpl[name]; ok {
+			if _, hasValue := p.current(); hasValue {
+				res = append(res, name)
+			}
+		}
+	}
+	return res
+}
+
+// current is the value and if it is set. A Loader can change it anytime after Init(), see startSync().
+func (p *paramImpl) current() (value string, hasValue bool) {
+	p.loadLock.Lock()
+	defer p.loadLock.Unlock()
+	return p.value, p.hasValue
+}
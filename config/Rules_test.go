@@ -0,0 +1,177 @@
+package config
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param"
+)
+
+func TestManager_Init_rules(t *testing.T) {
+	newManager := func(t *testing.T, validate func(s Snapshot) error) *Manager {
+		t.Helper()
+		noop := func(s string) error { return nil }
+		pCert, _ := param.NewString("TLSCert", noop, param.WithRequires("TLSKey"))
+		pKey, _ := param.NewString("TLSKey", noop)
+		pMode, _ := param.NewString("Mode", noop, param.WithDefault("local"))
+		pBucket, _ := param.NewString("Bucket", noop, param.WithRequiredIf("Mode", "s3"))
+		pToken, _ := param.NewString("Token", noop)
+		pPassword, _ := param.NewString("Password", noop)
+		pJSON, _ := param.NewBool("JSON", func(b bool) error { return nil }, param.WithRequires("Token"))
+		pText, _ := param.NewBool("Text", func(b bool) error { return nil })
+		cDeploy, err := New(WithParams(pJSON, pText), WithExactlyOneOf("JSON", "Text"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := New(
+			WithParams(pCert, pKey, pMode, pBucket, pToken, pPassword),
+			WithAtLeastOneOf("Token", "Password"),
+			WithSubCommand("deploy", cDeploy),
+			WithValidate(validate),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		validate func(s Snapshot) error
+		wantErrs []string
+	}{
+		{
+			name: "ok",
+			args: []string{"-Token=t", "-TLSCert=c", "-TLSKey=k", "-Mode=s3", "-Bucket=b"},
+		},
+		{
+			name: "all violations",
+			args: []string{"-TLSCert=c", "-Mode=s3", "deploy", "-JSON", "-Text"},
+			validate: func(s Snapshot) error {
+				if p, _ := s.Get("Mode"); p.Value == "s3" {
+					return fmt.Errorf("s3 is not available")
+				}
+				return nil
+			},
+			wantErrs: []string{
+				`ConfigError for Param:"Bucket": mandatory value when param:"Mode" is "s3"`,
+				`on SubCommands: [ deploy], ConfigError for Param:"JSON": requires param:"Token"`,
+				`ConfigError for Param:"TLSCert": requires param:"TLSKey"`,
+				`ConfigError: expect a value for at least one of params:[Token Password]`,
+				`on SubCommands: [ deploy], ConfigError: expect a value for exactly one of params:[JSON Text], got:[JSON Text]`,
+				`ConfigError: s3 is not available`,
+			},
+		},
+		{
+			name:     "exactly one, none",
+			args:     []string{"-Password=p", "deploy"},
+			wantErrs: []string{`on SubCommands: [ deploy], ConfigError: expect a value for exactly one of params:[JSON Text], got:[]`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newManager(t, tt.validate)
+			err := c.Init(context.Background(), WithInputArgs(tt.args))
+			got := []string{}
+			aggErr := errors.ConfigAggregatedError{}
+			if stderrors.As(err, &aggErr) {
+				for _, e := range aggErr.Errs {
+					got = append(got, e.Error())
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.wantErrs) || len(got) > 0 && !reflect.DeepEqual(got, tt.wantErrs) {
+				t.Errorf("Init() errors\ngot =%q\nwant=%q", got, tt.wantErrs)
+			}
+			if tt.name == "all violations" && !stderrors.Is(aggErr.Errs[0], errors.ErrMandatoryValue) {
+				t.Errorf("expect ErrMandatoryValue in %v", aggErr.Errs[0])
+			}
+		})
+	}
+
+	usage := newManager(t, nil).Usage(0)
+	for _, want := range []string{"Requires: [TLSKey]", "Mandatory if: Mode=s3", "At least one of: [Token Password]", "Exactly one of: [JSON Text]"} {
+		if !strings.Contains(usage, want) {
+			t.Errorf("usage\ngot =%s\nwant=%q", usage, want)
+		}
+	}
+
+	if _, err := New(WithAtLeastOneOf("Token")); err == nil {
+		t.Errorf("expect error for a group of 1 param")
+	}
+}
+
+func TestManager_Init_rulesUnknownParam(t *testing.T) {
+	noop := func(s string) error { return nil }
+	pA, _ := param.NewString("A", noop, param.WithRequires("Missing"))
+	pLocal, _ := param.NewString("Local", noop, param.WithIsSubCommandLocal(true))
+	pB, _ := param.NewString("B", noop, param.WithRequiredIf("Local", "x"), param.WithExclusive("A"))
+	cSub, err := New(WithParams(pB), WithExactlyOneOf("B", "Other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(pA, pLocal), WithSubCommand("sub", cSub), WithAtLeastOneOf("A", "Local"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Init(context.Background(), WithInputArgs([]string{}))
+	aggErr := errors.ConfigAggregatedError{}
+	if !stderrors.As(err, &aggErr) {
+		t.Fatalf("Init() expect ConfigAggregatedError, got:%v", err)
+	}
+	got := []string{}
+	for _, e := range aggErr.Errs {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		`ConfigError for Param:"A": unknown param:"Missing" in requires`,
+		`on SubCommands: [ sub], ConfigError for Param:"B": unknown param:"Local" in required if`,
+		`on SubCommands: [ sub], ConfigError for Param:"Other": unknown param in exactly one of:[B Other]`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Init() errors\ngot =%q\nwant=%q", got, want)
+	}
+	for _, e := range aggErr.Errs {
+		if !stderrors.As(e, &errors.ParamConfigError{}) {
+			t.Errorf("expect ParamConfigError, got:%T", e)
+		}
+	}
+}
+
+func TestManager_Reload_rules(t *testing.T) {
+	noop := func(s string) error { return nil }
+	t.Setenv("Token", "t")
+	pToken, _ := param.NewString("Token", noop)
+	pPassword, _ := param.NewString("Password", noop)
+	validated := 0
+	c, err := New(
+		WithParams(pToken, pPassword),
+		WithAtLeastOneOf("Token", "Password"),
+		WithValidate(func(s Snapshot) error { validated++; return nil }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{})); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Unsetenv("Token")
+	err = c.Reload(context.Background(), WithReloadEnvVar(true))
+	want := `ConfigError: expect a value for at least one of params:[Token Password]`
+	aggErr := errors.ConfigAggregatedError{}
+	if !stderrors.As(err, &aggErr) || len(aggErr.Errs) != 1 || aggErr.Errs[0].Error() != want {
+		t.Errorf("Reload()\ngot =%v\nwant=%q", err, want)
+	}
+	if validated != 2 {
+		t.Errorf("Validate calls\ngot =%d\nwant=%d", validated, 2)
+	}
+}
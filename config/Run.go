@@ -0,0 +1,86 @@
+package config
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/subcommand"
+)
+
+type (
+	// Invocation is what the command handler receives, see WithRun().
+	Invocation struct {
+		//SubCommands selected in the command line, starting with level 0.
+		SubCommands []subcommand.SubCommand
+
+		//Args are the positional args values, see WithArg().
+		Args ArgValues
+
+		//Snapshot of the values, once Init() is done.
+		Snapshot Snapshot
+	}
+)
+
+// WithRun is the command handler, called at the end of Init() when this Manager is the last subcommand in the command line.
+//
+// The context is the one given to Init(). An error is returned by Init() in a errors.RunError.
+func WithRun(f func(ctx context.Context, inv Invocation) error) configOptionsF {
+	return func(c *Manager) error {
+		c.Run = f
+		return nil
+	}
+}
+
+// WithPersistentPreRun is called before the Run of this Manager and all its SubCommands. The parents first.
+//
+// Like opening a DB for every subcommand under `admin`. Not called when the selected command has no Run.
+func WithPersistentPreRun(f func(ctx context.Context, inv Invocation) error) configOptionsF {
+	return func(c *Manager) error {
+		c.PersistentPreRun = f
+		return nil
+	}
+}
+
+// WithPersistentPostRun is called after the Run of this Manager and all its SubCommands. The children first.
+//
+// Called even when Run failed, as long as the PersistentPreRun of the same Manager succeeded. (Like a defer)
+func WithPersistentPostRun(f func(ctx context.Context, inv Invocation) error) configOptionsF {
+	return func(c *Manager) error {
+		c.PersistentPostRun = f
+		return nil
+	}
+}
+
+// run calls the hooks and the Run of the last Manager in the chain.
+func run(ctx context.Context, chain []*Manager, inv Invocation) error {
+	cmd := chain[len(chain)-1]
+	if cmd.Run == nil {
+		return nil
+	}
+	var err error
+	started := 0
+	for _, m := range chain {
+		if m.PersistentPreRun != nil {
+			if err = m.PersistentPreRun(ctx, inv); err != nil {
+				break
+			}
+		}
+		started++
+	}
+	if err == nil {
+		err = cmd.Run(ctx, inv)
+	}
+	for i := started - 1; i >= 0; i-- {
+		if chain[i].PersistentPostRun == nil {
+			continue
+		}
+		if errPost := chain[i].PersistentPostRun(ctx, inv); errPost != nil {
+			err = stderrors.Join(err, errPost)
+		}
+	}
+	if err != nil {
+		return errors.RunError{SubCommands: inv.SubCommands, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param"
+)
+
+func TestManager_Init_run(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		failAdmin bool
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "hooks around run",
+			args: []string{"admin", "users", "-Region=eu", "bob"},
+			want: "pre root, pre admin, run [ admin users] [bob] eu, post admin, post root",
+		},
+		{
+			name:      "pre run fails",
+			args:      []string{"admin", "users", "bob"},
+			failAdmin: true,
+			want:      "pre root, pre admin, post root",
+			wantErr:   true,
+		},
+		{
+			name: "no run for the selected command",
+			args: []string{"admin"},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := []string{}
+			hook := func(name string, fail bool) func(ctx context.Context, inv Invocation) error {
+				return func(ctx context.Context, inv Invocation) error {
+					calls = append(calls, name)
+					if fail {
+						return fmt.Errorf("fail %s", name)
+					}
+					return nil
+				}
+			}
+			pRegion, err := param.New("Region", func(s string) error { return nil })
+			if err != nil {
+				t.Fatal(err)
+			}
+			cUsers, err := New(
+				WithParams(pRegion),
+				WithArg("user"),
+				WithRun(func(ctx context.Context, inv Invocation) error {
+					region, _ := inv.Snapshot.Get("Region")
+					calls = append(calls, fmt.Sprintf("run %q %q %s", inv.SubCommands, inv.Args["user"], region.Value))
+					return nil
+				}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cAdmin, err := New(
+				WithSubCommand("users", cUsers),
+				WithPersistentPreRun(hook("pre admin", tt.failAdmin)),
+				WithPersistentPostRun(hook("post admin", false)),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := New(
+				WithSubCommand("admin", cAdmin),
+				WithPersistentPreRun(hook("pre root", false)),
+				WithPersistentPostRun(hook("post root", false)),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = c.Init(context.Background(), WithInputArgs(tt.args))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !stderrors.As(err, &errors.RunError{}) {
+				t.Errorf("Init() expect RunError, got =%s", err)
+			}
+			got := strings.ReplaceAll(strings.Join(calls, ", "), `"`, "")
+			if got != tt.want {
+				t.Errorf("Init() calls\ngot =%s\nwant=%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"sort"
+	"time"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/paramname"
+	"github.com/vincentkerdraon/configo/config/param/source"
+	"github.com/vincentkerdraon/configo/config/subcommand"
+	"github.com/vincentkerdraon/configo/secretrotation"
+)
+
+type (
+	// Snapshot is the effective configuration: the values currently used and where they come from.
+	Snapshot struct {
+		//SubCommands selected during Init(), starting with level 0.
+		SubCommands []subcommand.SubCommand
+
+		//Params are all the params selected during Init(), including the subcommand params. Sorted by name.
+		Params []ParamSnapshot
+	}
+
+	// ParamSnapshot is the state of one param.
+	ParamSnapshot struct {
+		Name paramname.ParamName
+
+		//SubCommands where the param is declared, starting with level 0.
+		SubCommands []subcommand.SubCommand
+
+		//Value is the raw string value. Redacted when sensitive.
+		Value string
+
+		//Source is where Value comes from.
+		Source source.Source
+
+		//Overridden are the other sources with a value, losing against Source. Highest priority first.
+		Overridden []SourceValue
+
+		//LoadedAt is the last time the value was read.
+		LoadedAt time.Time
+
+		IsSensitive bool
+	}
+
+	// SourceValue is a raw value found in a source.
+	SourceValue struct {
+		Source source.Source
+		Value  string
+	}
+)
+
+// Snapshot returns the effective configuration.
+//
+// Empty before Init().
+func (c *Manager) Snapshot() Snapshot {
+	res := Snapshot{
+		SubCommands: append([]subcommand.SubCommand{}, c.subCommandsInit...),
+		Params:      []ParamSnapshot{},
+	}
+	for _, p := range c.paramsImpl {
+		res.Params = append(res.Params, p.snapshot())
+	}
+	sort.Slice(res.Params, func(i, j int) bool { return res.Params[i].Name < res.Params[j].Name })
+	return res
+}
+
+// Get finds a param by name.
+func (s Snapshot) Get(name paramname.ParamName) (ParamSnapshot, bool) {
+	for _, p := range s.Params {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return ParamSnapshot{}, false
+}
+
+func (p *paramImpl) snapshot() ParamSnapshot {
+	//Only blocking when a Loader is running for this param.
+	p.loadLock.Lock()
+	defer p.loadLock.Unlock()
+
+	res := ParamSnapshot{
+		Name:        p.Name,
+		SubCommands: append([]subcommand.SubCommand{}, p.subCommands...),
+		Value:       p.redact(p.value),
+		Source:      p.source,
+		Overridden:  p.overridden(),
+		LoadedAt:    p.loadedAt,
+		IsSensitive: p.IsSensitive,
+	}
+	for i := range res.Overridden {
+		res.Overridden[i].Value = p.redact(res.Overridden[i].Value)
+	}
+	return res
+}
+
+// redact hides the value when the param is sensitive.
+func (p paramImpl) redact(s string) string {
+	if !p.IsSensitive || s == "" {
+		return s
+	}
+	return secretrotation.SecretRedacted
+}
+
+// redactErr scrubs the values of a sensitive param in the error message, like an input echoed by Parse.
+//
+// The values are the ones found in all the sources, plus the extra values.
+// For a slice or a map, each item is scrubbed too, like `b` in `a,b`.
+func (p paramImpl) redactErr(err error, extra ...string) error {
+	if !p.IsSensitive || err == nil {
+		return err
+	}
+	raw := append([]string{p.value, p.Default}, extra...)
+	for _, v := range p.values {
+		raw = append(raw, v)
+	}
+	values := []string{}
+	for _, v := range raw {
+		values = append(values, p.items(v)...)
+	}
+	return errors.SensitiveError{Err: err, Redact: func(in string) string {
+		return errors.RedactValues(in, values...)
+	}}
+}
+
+// items are the value, and the items of a slice or a map: `k=v` gives `k=v` and `v`.
+func (p paramImpl) items(v string) []string {
+	res := []string{v}
+	sep := p.ListSeparator()
+	if sep == "" {
+		return res
+	}
+	for _, item := range param.SplitList(v, sep) {
+		res = append(res, item)
+		if _, val, err := param.SplitKeyValue(item); err == nil && p.IsMap() {
+			res = append(res, val)
+		}
+	}
+	return res
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/source"
+	"github.com/vincentkerdraon/configo/config/subcommand"
+)
+
+func TestManager_Snapshot(t *testing.T) {
+	t.Setenv("CONFIGO_TEST_SNAPSHOT_TIMEOUT", "20s")
+	noop := func(s string) error { return nil }
+	pTimeout, _ := param.New("Timeout", noop,
+		param.WithDefault("10s"),
+		param.WithEnvVar(param.WithEnvVarName("CONFIGO_TEST_SNAPSHOT_TIMEOUT")),
+	)
+	pPassword, _ := param.New("Password", noop,
+		param.WithLoader(func(ctx context.Context) (string, error) { return "secret", nil }),
+		param.WithSensitive(),
+	)
+	pRegion, _ := param.New("Region", noop, param.WithDefault("ca-central-1"))
+	pName, _ := param.New("Name", noop)
+	cSub, _ := New(WithParams(pRegion))
+	c, _ := New(WithParams(pTimeout, pPassword, pName), WithSubCommand("deploy", cSub))
+
+	if got := c.Snapshot(); len(got.Params) != 0 {
+		t.Errorf("Snapshot before Init()\ngot =%+v\nwant=empty", got)
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{"deploy", "-Timeout=30s"})); err != nil {
+		t.Fatal(err)
+	}
+	got := c.Snapshot()
+	if fmt.Sprint(got.SubCommands) != fmt.Sprint([]subcommand.SubCommand{subCommandLevel0, "deploy"}) {
+		t.Errorf("Snapshot SubCommands\ngot =%v", got.SubCommands)
+	}
+
+	tests := []struct {
+		name           string
+		wantValue      string
+		wantSource     source.Source
+		wantOverridden string
+	}{
+		{name: "Name", wantValue: "", wantSource: source.None, wantOverridden: "[]"},
+		{name: "Password", wantValue: "[redacted]", wantSource: source.Loader, wantOverridden: "[]"},
+		{name: "Region", wantValue: "ca-central-1", wantSource: source.Default, wantOverridden: "[]"},
+		{name: "Timeout", wantValue: "30s", wantSource: source.Flag, wantOverridden: "[{EnvVar 20s} {Default 10s}]"},
+	}
+	if len(got.Params) != len(tests) {
+		t.Fatalf("Snapshot Params\ngot =%+v", got.Params)
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := got.Params[i]
+			if p.Name.String() != tt.name || p.Value != tt.wantValue || p.Source != tt.wantSource || fmt.Sprint(p.Overridden) != tt.wantOverridden {
+				t.Errorf("ParamSnapshot\ngot =%+v\nwant=%+v", p, tt)
+			}
+			if p.LoadedAt.IsZero() {
+				t.Errorf("ParamSnapshot LoadedAt not set")
+			}
+		})
+	}
+}
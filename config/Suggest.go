@@ -0,0 +1,174 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"log/slog"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/subcommand"
+)
+
+// suggestMax is the number of suggestions kept, closest first.
+const suggestMax = 3
+
+// WithEnvVarCheck warns (see WithLogger()) about the env vars starting with this prefix, but not matching any param.
+// Like `MYAPP_TIMOUT` instead of `MYAPP_TIMEOUT`, silently ignored otherwise.
+//
+// Checked once during Init(), against the params of this Manager and all its SubCommands.
+func WithEnvVarCheck(prefix string) configOptionsF {
+	return func(c *Manager) error {
+		if prefix == "" {
+			return errors.ConfigError{Err: fmt.Errorf("mandatory env var prefix when using the option")}
+		}
+		c.EnvVarCheckPrefix = prefix
+		return nil
+	}
+}
+
+// suggest finds the closest candidates, using the edit distance. A candidate starting with name also matches.
+//
+// candidates are the suggestions, with the names to compare. Like a subcommand and its aliases.
+func suggest(name string, candidates map[string][]string) []string {
+	type match struct {
+		suggestion string
+		distance   int
+	}
+	maxDistance := len(name) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+	matches := []match{}
+	for suggestion, keys := range candidates {
+		best := -1
+		for _, key := range keys {
+			if key == "" {
+				continue
+			}
+			d := levenshtein(strings.ToLower(name), strings.ToLower(key))
+			if d > maxDistance && !(name != "" && strings.HasPrefix(key, name)) {
+				continue
+			}
+			if best == -1 || d < best {
+				best = d
+			}
+		}
+		if best != -1 {
+			matches = append(matches, match{suggestion: suggestion, distance: best})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].suggestion < matches[j].suggestion
+	})
+	res := []string{}
+	for i := 0; i < len(matches) && i < suggestMax; i++ {
+		res = append(res, matches[i].suggestion)
+	}
+	return res
+}
+
+// levenshtein is the number of single character edits to change a into b.
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// suggestFlags finds the flags close to the unknown flag in the std flag package error. Also comparing with the env var names.
+func suggestFlags(style FlagStyle, defs []*flagDef, err error) []string {
+	name := strings.TrimLeft(strings.TrimSpace(strings.TrimPrefix(err.Error(), errFlagProvidedNotDefined)), "-")
+	dashes := "-"
+	if style == FlagStyleGNU {
+		dashes = "--"
+	}
+	candidates := map[string][]string{}
+	for _, d := range defs {
+		candidates[dashes+d.name] = []string{d.name, d.envVar}
+	}
+	return suggest(name, candidates)
+}
+
+// suggestSubCommands finds the subcommands close to name, also comparing with the aliases. Hidden subcommands are skipped.
+func (c *Manager) suggestSubCommands(name string) []string {
+	candidates := map[string][]string{}
+	for subCmd, sub := range c.SubCommands {
+		if sub.Hidden {
+			continue
+		}
+		keys := []string{subCmd.String()}
+		for _, alias := range sub.Aliases {
+			keys = append(keys, alias.String())
+		}
+		candidates[subCmd.String()] = keys
+	}
+	return suggest(name, candidates)
+}
+
+// envVarNames are the env var names of the params of this Manager and all its SubCommands.
+func (c *Manager) envVarNames() []string {
+	return c.envVarNamesIn(c, nil)
+}
+
+// envVarNamesIn with the root Manager for the naming, and the path of this subcommand.
+func (c *Manager) envVarNamesIn(root *Manager, path []subcommand.SubCommand) []string {
+	res := []string{}
+	for _, p := range c.Params {
+		if p.EnvVar.Use {
+			res = append(res, paramImpl{Param: p, naming: root.naming(path)}.envVarName())
+		}
+	}
+	for subCmd, sub := range c.SubCommands {
+		res = append(res, sub.envVarNamesIn(root, append(append([]subcommand.SubCommand{}, path...), subCmd))...)
+	}
+	return res
+}
+
+// checkEnvVars warns about the env vars using the prefix, but not matching any param. See WithEnvVarCheck().
+func (c *Manager) checkEnvVars(ctx context.Context, environ []string) {
+	if c.EnvVarCheckPrefix == "" {
+		return
+	}
+	known := map[string]bool{}
+	candidates := map[string][]string{}
+	for _, name := range c.envVarNames() {
+		known[name] = true
+		known[name+envVarFileSuffix] = true
+		candidates[name] = []string{name}
+	}
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, c.EnvVarCheckPrefix) || known[name] {
+			continue
+		}
+		c.Logger.WarnContext(ctx, "env var not matching any param", slog.String("envVar", name), slog.Any("didYouMean", suggest(name, candidates)))
+	}
+}
+
+// sortedEnviron is os.Environ(), sorted to log in the same order every time.
+func sortedEnviron() []string {
+	res := os.Environ()
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package config
+
+// terminalWidthFd is unknown on this platform, no wrapping.
+func terminalWidthFd(fd uintptr) int {
+	return 0
+}
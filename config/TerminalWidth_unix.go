@@ -0,0 +1,17 @@
+//go:build linux || darwin
+
+package config
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// terminalWidthFd is the number of columns of the terminal. 0 when not a terminal.
+func terminalWidthFd(fd uintptr) int {
+	var ws struct{ row, col, xPixel, yPixel uint16 }
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws))); errno != 0 {
+		return 0
+	}
+	return int(ws.col)
+}
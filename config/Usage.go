@@ -2,35 +2,20 @@ package config
 
 import (
 	stderrors "errors"
-	"fmt"
 	"strings"
 
 	"github.com/vincentkerdraon/configo/config/errors"
 	"github.com/vincentkerdraon/configo/config/param"
 	"github.com/vincentkerdraon/configo/config/param/paramname"
+	"github.com/vincentkerdraon/configo/config/param/source"
 	"github.com/vincentkerdraon/configo/config/subcommand"
 )
 
 // Usage displays how to use this configuration.
+//
+// The params and subcommands are in declaration order, the lines wrapped at the width of WithUsageWidth().
 func (c Manager) Usage(indentation int) string {
-	indentString := strings.Repeat("\t", indentation)
-	var res string
-	append := func(s string) {
-		res += "\n" + indentString + s
-	}
-	if c.Description != "" {
-		append("Config/Command description: " + c.Description + "\n")
-	} else {
-		append("")
-	}
-	for _, p := range c.Params {
-		pi := paramImpl{Param: p}
-		append(pi.usage(indentation + 1))
-	}
-	for command, config := range c.SubCommands {
-		append(fmt.Sprintf("Command: %s\n%s", command, config.Usage(indentation+1)))
-	}
-	return fmt.Sprintf("%s\n", res)
+	return c.wrap(renderText(c.UsageModel(), indentation, textStylePlain))
 }
 
 // usageWhenConfigError is encapsulating the error to add usage notes.
@@ -39,49 +24,117 @@ func (c Manager) Usage(indentation int) string {
 func (c *Manager) usageWhenConfigError(err error) error {
 	pce := errors.ParamConfigError{}
 	if stderrors.As(err, &pce) {
-		p := c.getParamInSubCommands(pce.SubCommands, pce.ParamName)
+		p, path := c.getParamInSubCommands(pce.SubCommands, pce.ParamName)
 		if p == nil {
 			return err
 		}
-		pi := paramImpl{Param: *p}
+		pi := paramImpl{Param: *p, priority: paramPriority(*p, c.pathPriority(path)), flagStyle: c.FlagStyle, naming: c.naming(path)}
 		return errors.ConfigWithUsageError{
 			Err:   err,
-			Usage: pi.usage(1),
+			Usage: c.wrap(pi.usage(1)),
 		}
 	}
 	ce := errors.ConfigError{}
 	if stderrors.As(err, &ce) {
-		cmd := c.getSubCommand(ce.SubCommands)
+		flagUnknownError := errors.FlagUnknownError{}
+		isFlagUnknown := stderrors.As(err, &flagUnknownError)
+		subCommandUnknownError := errors.SubCommandUnknownError{}
+		isSubCommandUnknown := stderrors.As(err, &subCommandUnknownError)
+
+		path := ce.SubCommands
+		cmd := c.getSubCommand(path)
+		if cmd == nil && isSubCommandUnknown && len(path) > 0 {
+			//Usage of the parent
+			path = path[:len(path)-1]
+			cmd = c.getSubCommand(path)
+		}
 		if cmd == nil {
-			flagUnknownError := errors.FlagUnknownError{}
-			if stderrors.As(err, &flagUnknownError) {
+			if isFlagUnknown {
 				return errors.ConfigWithUsageError{
-					Err:   err,
-					Usage: c.Usage(0),
+					Err:         err,
+					Usage:       c.Usage(0),
+					Suggestions: flagUnknownError.Suggestions,
 				}
 			}
 			return err
 		}
-		return errors.ConfigWithUsageError{
+		res := errors.ConfigWithUsageError{
 			Err:   err,
-			Usage: cmd.Usage(0),
+			Usage: c.wrap(renderText(cmd.usageModel(c, withoutLevel0(path), c.pathPriority(path)), 0, textStylePlain)),
+		}
+		if isFlagUnknown {
+			res.Suggestions = flagUnknownError.Suggestions
 		}
+		if isSubCommandUnknown {
+			res.Suggestions = subCommandUnknownError.Suggestions
+		}
+		return res
 	}
 	return err
 }
 
-func (c *Manager) getParamInSubCommands(subCommands []subcommand.SubCommand, paramName paramname.ParamName) *param.Param {
+// orderedParams are the params in declaration order. The params added directly in the map are last, sorted by name.
+func (c Manager) orderedParams() []param.Param {
+	res := make([]param.Param, 0, len(c.Params))
+	seen := map[paramname.ParamName]bool{}
+	for _, name := range c.paramsOrder {
+		if p, f := c.Params[name]; f && !seen[name] {
+			seen[name] = true
+			res = append(res, p)
+		}
+	}
+	for _, p := range c.sortedParams() {
+		if !seen[p.Name] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// orderedSubCommands are the subcommands in declaration order. The subcommands added directly in the map are last, sorted by name.
+func (c Manager) orderedSubCommands() []subcommand.SubCommand {
+	res := make([]subcommand.SubCommand, 0, len(c.SubCommands))
+	seen := map[subcommand.SubCommand]bool{}
+	for _, subCmd := range c.subCommandsOrder {
+		if _, f := c.SubCommands[subCmd]; f && !seen[subCmd] {
+			seen[subCmd] = true
+			res = append(res, subCmd)
+		}
+	}
+	for _, subCmd := range c.sortedSubCommands() {
+		if !seen[subCmd] {
+			res = append(res, subCmd)
+		}
+	}
+	return res
+}
+
+// getParamInSubCommands returns the param and the path of the subcommand declaring it.
+func (c *Manager) getParamInSubCommands(subCommands []subcommand.SubCommand, paramName paramname.ParamName) (*param.Param, []subcommand.SubCommand) {
 	var m *Manager = c
-	for _, subCmd := range subCommands {
+	for i, subCmd := range subCommands {
 		if subCmd != subCommandLevel0 {
-			m = m.SubCommands[subCmd]
+			if m = m.SubCommands[subCmd]; m == nil {
+				return nil, nil
+			}
 		}
 		p, f := m.Params[paramName]
 		if f {
-			return &p
+			return &p, subCommands[:i+1]
+		}
+	}
+	return nil, nil
+}
+
+// withoutLevel0 removes the root Manager from the path.
+func withoutLevel0(subCommands []subcommand.SubCommand) []subcommand.SubCommand {
+	res := []subcommand.SubCommand{}
+	for _, subCmd := range subCommands {
+		if subCmd != subCommandLevel0 {
+			res = append(res, subCmd)
 		}
 	}
-	return nil
+	return res
 }
 
 func (c *Manager) getSubCommand(subCommands []subcommand.SubCommand) *Manager {
@@ -91,7 +144,36 @@ func (c *Manager) getSubCommand(subCommands []subcommand.SubCommand) *Manager {
 			res = c
 			continue
 		}
-		res = c.SubCommands[subCmd]
+		if res == nil {
+			res = c
+		}
+		if res = res.SubCommands[subCmd]; res == nil {
+			return nil
+		}
 	}
 	return res
 }
+
+// pathPriority is the source priority inherited along the subcommands, nil for the default.
+func (c *Manager) pathPriority(subCommands []subcommand.SubCommand) []source.Source {
+	m := c
+	priority := c.inheritPriority(nil)
+	for _, subCmd := range subCommands {
+		if subCmd == subCommandLevel0 {
+			continue
+		}
+		if m = m.SubCommands[subCmd]; m == nil {
+			return priority
+		}
+		priority = m.inheritPriority(priority)
+	}
+	return priority
+}
+
+func joinSubCommands(subCommands []subcommand.SubCommand) string {
+	res := make([]string, 0, len(subCommands))
+	for _, s := range subCommands {
+		res = append(res, s.String())
+	}
+	return strings.Join(res, ", ")
+}
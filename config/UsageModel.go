@@ -0,0 +1,224 @@
+package config
+
+import (
+	"time"
+
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/paramname"
+	"github.com/vincentkerdraon/configo/config/param/source"
+	"github.com/vincentkerdraon/configo/config/subcommand"
+)
+
+type (
+	// UsageCommand is the usage of the root Manager or of a subcommand, see Manager.UsageModel().
+	//
+	// The data to write a custom help, see UsageRenderer.
+	UsageCommand struct {
+		//Path are the subcommands names, empty for the root Manager.
+		Path        []subcommand.SubCommand
+		Description string
+		Aliases     []subcommand.SubCommand
+		Deprecated  string
+
+		//ArgsSynopsis is the command line form of the args, like `<src> <dst> [files...]`
+		ArgsSynopsis string
+		Args         []UsageArg
+
+		//SourcePriority when defined on this Manager, see WithSourcePriority().
+		SourcePriority []source.Source
+		ConfigFiles    []ConfigFile
+		DotEnvFiles    []string
+
+		//AtLeastOneOf and ExactlyOneOf are the groups of params needing a value, see WithAtLeastOneOf().
+		AtLeastOneOf [][]paramname.ParamName
+		ExactlyOneOf [][]paramname.ParamName
+
+		//Params without group, in declaration order. The hidden params are skipped.
+		Params []UsageParam
+		//Groups of params, in declaration order, see param.WithGroup().
+		Groups []UsageGroup
+
+		//Commands are the subcommands, in declaration order. The hidden subcommands are skipped.
+		Commands []UsageCommand
+	}
+
+	// UsageGroup is a section of params, see param.WithGroup().
+	UsageGroup struct {
+		Name   string
+		Params []UsageParam
+	}
+
+	// UsageArg is a positional arg, see WithArg().
+	UsageArg struct {
+		Name string
+		Desc string
+		Min  int
+		Max  int
+		//Arity is the number of values, like `at least 1 value`
+		Arity string
+	}
+
+	// UsageParam is the usage of a param.
+	UsageParam struct {
+		Name        paramname.ParamName
+		Description string
+		Examples    []string
+		//Default is redacted when the param is sensitive.
+		Default     string
+		Group       string
+		Sources     UsageSources
+		Constraints UsageConstraints
+	}
+
+	// UsageSources are where the param value is read.
+	UsageSources struct {
+		//Priority when defined on this param, see param.WithSourcePriority().
+		Priority []source.Source
+
+		//Flags are the ways to write the flag, long name first. Like `--verbose, -v`. Empty when the flag is not read.
+		Flags []string
+		//IsSwitch for a flag without value, like `-verbose` for `-verbose=true`.
+		IsSwitch bool
+
+		//Separator splits the value of a slice or map param. Empty otherwise.
+		Separator string
+
+		//EnvVar is the env var name. Empty when the env var is not read.
+		EnvVar string
+
+		//Loader when a custom loader is read.
+		Loader bool
+		//LoaderFrequency is the refresh period of the loader. 0 means only at startup.
+		LoaderFrequency time.Duration
+		//LoaderAlwaysSync when the loader refreshes even when a higher priority source has a value.
+		LoaderAlwaysSync bool
+	}
+
+	// UsageConstraints are the rules on the param value.
+	UsageConstraints struct {
+		IsMandatory       bool
+		EnumValues        []string
+		Exclusive         []paramname.ParamName
+		Requires          []paramname.ParamName
+		IsSubCommandLocal bool
+
+		//EnumCaseInsensitive when the EnumValues match ignoring the case.
+		EnumCaseInsensitive bool
+
+		//RequiredIf are the conditions making the param mandatory, like `Mode=s3`.
+		RequiredIf []string
+
+		//Validations are the built-in validators, like `min:1`. See param.WithValidator().
+		Validations []string
+	}
+)
+
+// UsageModel is the usage of this Manager and all its SubCommands, as data.
+//
+// Used by the renderers, see WithUsageRenderer().
+func (c Manager) UsageModel() UsageCommand {
+	return c.usageModel(&c, nil, nil)
+}
+
+// usageModel with the root Manager (for the flag style and the naming), the path of this subcommand and the priority inherited from the parents.
+func (c Manager) usageModel(root *Manager, path []subcommand.SubCommand, priority []source.Source) UsageCommand {
+	priority = c.inheritPriority(priority)
+	res := UsageCommand{
+		Path:           path,
+		Description:    c.Description,
+		Aliases:        c.Aliases,
+		Deprecated:     c.Deprecated,
+		ArgsSynopsis:   c.argsUsage(),
+		Args:           []UsageArg{},
+		SourcePriority: c.SourcePriority,
+		ConfigFiles:    c.ConfigFiles,
+		DotEnvFiles:    c.DotEnvFiles,
+		AtLeastOneOf:   c.AtLeastOneOf,
+		ExactlyOneOf:   c.ExactlyOneOf,
+		Params:         []UsageParam{},
+		Groups:         []UsageGroup{},
+		Commands:       []UsageCommand{},
+	}
+	for _, a := range c.Args {
+		res.Args = append(res.Args, UsageArg{Name: a.Name, Desc: a.Desc, Min: a.Min, Max: a.Max, Arity: a.arity()})
+	}
+	groups := map[string]int{}
+	for _, p := range c.orderedParams() {
+		if p.IsHidden {
+			continue
+		}
+		pi := paramImpl{Param: p, priority: paramPriority(p, priority), flagStyle: root.FlagStyle, naming: root.naming(path)}
+		up := pi.usageModel()
+		if p.Group == "" {
+			res.Params = append(res.Params, up)
+			continue
+		}
+		i, f := groups[p.Group]
+		if !f {
+			i = len(res.Groups)
+			groups[p.Group] = i
+			res.Groups = append(res.Groups, UsageGroup{Name: p.Group})
+		}
+		res.Groups[i].Params = append(res.Groups[i].Params, up)
+	}
+	for _, subCmd := range c.orderedSubCommands() {
+		sub := c.SubCommands[subCmd]
+		if sub.Hidden {
+			continue
+		}
+		subPath := append(append([]subcommand.SubCommand{}, path...), subCmd)
+		res.Commands = append(res.Commands, sub.usageModel(root, subPath, priority))
+	}
+	return res
+}
+
+// usageModel is the usage of this param, with the flag style, the naming and the priority already set.
+func (p paramImpl) usageModel() UsageParam {
+	res := UsageParam{
+		Name:        p.Name,
+		Description: p.Desc,
+		Examples:    p.Examples,
+		Default:     p.redact(p.Default),
+		Group:       p.Group,
+		Sources: UsageSources{
+			Priority:  p.SourcePriority,
+			Separator: p.ListSeparator(),
+		},
+		Constraints: UsageConstraints{
+			IsMandatory:         p.IsMandatory,
+			EnumValues:          p.EnumValues,
+			EnumCaseInsensitive: p.EnumCaseInsensitive,
+			Exclusive:           p.Exclusive,
+			Requires:            p.Requires,
+			RequiredIf:          requiredIf(p.RequiredIf),
+			IsSubCommandLocal:   p.IsSubCommandLocal,
+		},
+	}
+	for _, v := range p.Validators {
+		if v.Desc != "" {
+			res.Constraints.Validations = append(res.Constraints.Validations, v.Desc)
+		}
+	}
+	if p.Flag.Use && p.reads(source.Flag) {
+		res.Sources.Flags = p.flagForms()
+		res.Sources.IsSwitch = p.isBool()
+	}
+	if p.EnvVar.Use && p.reads(source.EnvVar) {
+		res.Sources.EnvVar = p.envVarName()
+	}
+	if p.Loader.Getter != nil && p.reads(source.Loader) {
+		res.Sources.Loader = true
+		res.Sources.LoaderFrequency = p.Loader.SynchroFrequency
+		res.Sources.LoaderAlwaysSync = p.Loader.AlwaysSync
+	}
+	return res
+}
+
+// requiredIf are the conditions, like `Mode=s3`.
+func requiredIf(conditions []param.Condition) []string {
+	res := []string{}
+	for _, cond := range conditions {
+		res = append(res, cond.Param.String()+"="+cond.Value)
+	}
+	return res
+}
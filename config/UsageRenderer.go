@@ -0,0 +1,281 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param/source"
+)
+
+type (
+	// UsageRenderer writes the usage of a command. See WithUsageRenderer().
+	UsageRenderer interface {
+		RenderUsage(w io.Writer, cmd UsageCommand) error
+	}
+
+	// TextRenderer is the plain text usage, like Manager.Usage().
+	TextRenderer struct {
+		//Indentation is the number of tabs in front of every line.
+		Indentation int
+		//Width to wrap the lines, see WithUsageWidth().
+		//
+		// default: 0, no wrapping
+		Width int
+	}
+
+	// ColorRenderer is the text usage, with ANSI colors when writing to a terminal.
+	//
+	// Plain text otherwise, or with the env var NO_COLOR. See https://no-color.org
+	ColorRenderer struct {
+		TextRenderer
+	}
+
+	// TemplateRenderer executes a text/template, with the UsageCommand as data.
+	//
+	// See NewTemplateRenderer() for the template functions.
+	TemplateRenderer struct {
+		Template *template.Template
+	}
+
+	// textStyle is how to highlight the text usage.
+	textStyle struct {
+		//title for the names, like `Param: name`
+		title func(s string) string
+		//value for what is written by the user, like the flags.
+		value func(s string) string
+	}
+)
+
+const (
+	ansiBold  = "\x1b[1m"
+	ansiCyan  = "\x1b[36m"
+	ansiReset = "\x1b[0m"
+)
+
+var (
+	textStylePlain = textStyle{
+		title: func(s string) string { return s },
+		value: func(s string) string { return s },
+	}
+	textStyleColor = textStyle{
+		title: func(s string) string { return ansiBold + s + ansiReset },
+		value: func(s string) string { return ansiCyan + s + ansiReset },
+	}
+)
+
+// WithUsageRenderer is how the usage is written for the built-in help, and by Manager.WriteUsage().
+//
+// default: TextRenderer
+func WithUsageRenderer(r UsageRenderer) configOptionsF {
+	return func(c *Manager) error {
+		if r == nil {
+			return errors.ConfigError{Err: fmt.Errorf("usage renderer can't be nil")}
+		}
+		c.UsageRenderer = r
+		return nil
+	}
+}
+
+// WriteUsage writes the usage, with the renderer defined by WithUsageRenderer().
+func (c Manager) WriteUsage(w io.Writer) error {
+	return c.usageRenderer().RenderUsage(w, c.UsageModel())
+}
+
+func (c Manager) usageRenderer() UsageRenderer {
+	if c.UsageRenderer != nil {
+		return c.UsageRenderer
+	}
+	return TextRenderer{Width: c.UsageWidth}
+}
+
+func (r TextRenderer) RenderUsage(w io.Writer, cmd UsageCommand) error {
+	_, err := io.WriteString(w, wrapLines(renderText(cmd, r.Indentation, textStylePlain), r.Width))
+	return err
+}
+
+func (r ColorRenderer) RenderUsage(w io.Writer, cmd UsageCommand) error {
+	style := textStylePlain
+	if f, ok := w.(*os.File); ok && os.Getenv("NO_COLOR") == "" && terminalWidthFd(f.Fd()) > 0 {
+		style = textStyleColor
+	}
+	_, err := io.WriteString(w, wrapLines(renderText(cmd, r.Indentation, style), r.Width))
+	return err
+}
+
+// NewTemplateRenderer parses the template. The data is the UsageCommand.
+//
+// Functions added to the template:
+//   - join: `{{join ", " .Sources.Flags}}`, for any slice.
+//   - repeat: `{{repeat "\t" 2}}`
+//   - priority: `{{priority .SourcePriority}}`, like `Flag > EnvVar`
+func NewTemplateRenderer(text string) (TemplateRenderer, error) {
+	t, err := template.New("usage").Funcs(template.FuncMap{
+		"join":     templateJoin,
+		"repeat":   strings.Repeat,
+		"priority": source.FormatPriority,
+	}).Parse(text)
+	if err != nil {
+		return TemplateRenderer{}, errors.ConfigError{Err: fmt.Errorf("usage template, %w", err)}
+	}
+	return TemplateRenderer{Template: t}, nil
+}
+
+func (r TemplateRenderer) RenderUsage(w io.Writer, cmd UsageCommand) error {
+	if r.Template == nil {
+		return errors.ConfigError{Err: fmt.Errorf("mandatory usage template")}
+	}
+	return r.Template.Execute(w, cmd)
+}
+
+// templateJoin joins the elements of any slice, like []subcommand.SubCommand.
+func templateJoin(sep string, in any) string {
+	v := reflect.ValueOf(in)
+	if v.Kind() != reflect.Slice {
+		return fmt.Sprint(in)
+	}
+	res := make([]string, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		res = append(res, fmt.Sprint(v.Index(i).Interface()))
+	}
+	return strings.Join(res, sep)
+}
+
+// renderText is the text usage of the command and all its subcommands.
+func renderText(cmd UsageCommand, indentation int, style textStyle) string {
+	indentString := strings.Repeat("\t", indentation)
+	var res string
+	append := func(s string) {
+		res += "\n" + indentString + s
+	}
+	if cmd.Description != "" {
+		append("Config/Command description: " + cmd.Description + "\n")
+	} else {
+		append("")
+	}
+	if len(cmd.Args) > 0 {
+		append("Args: " + style.value(cmd.ArgsSynopsis))
+		for _, a := range cmd.Args {
+			if a.Desc != "" {
+				append(fmt.Sprintf("\t%s: %s (%s)", style.title(a.Name), a.Desc, a.Arity))
+			} else {
+				append(fmt.Sprintf("\t%s: %s", style.title(a.Name), a.Arity))
+			}
+		}
+		append("")
+	}
+	if cmd.SourcePriority != nil {
+		append("Source priority: " + source.FormatPriority(cmd.SourcePriority) + "\n")
+	}
+	for _, f := range cmd.ConfigFiles {
+		append(fmt.Sprintf("Config file: %s (%s)\n", style.value(f.Path), f.Format))
+	}
+	for _, f := range cmd.DotEnvFiles {
+		append(fmt.Sprintf(".env file: %s\n", style.value(f)))
+	}
+	for _, group := range cmd.AtLeastOneOf {
+		append(fmt.Sprintf("At least one of: %v\n", group))
+	}
+	for _, group := range cmd.ExactlyOneOf {
+		append(fmt.Sprintf("Exactly one of: %v\n", group))
+	}
+	for _, p := range cmd.Params {
+		append(renderTextParam(p, indentation+1, style))
+	}
+	for _, g := range cmd.Groups {
+		append("\tGroup: " + style.title(g.Name))
+		for _, p := range g.Params {
+			append(renderTextParam(p, indentation+2, style))
+		}
+	}
+	for _, sub := range cmd.Commands {
+		name := style.title(sub.Path[len(sub.Path)-1].String())
+		if len(sub.Aliases) > 0 {
+			name += fmt.Sprintf(" (aliases: %s)", joinSubCommands(sub.Aliases))
+		}
+		if sub.Deprecated != "" {
+			name += fmt.Sprintf(" (deprecated, %s)", sub.Deprecated)
+		}
+		append(fmt.Sprintf("Command: %s\n%s", name, renderText(sub, indentation+1, style)))
+	}
+	return fmt.Sprintf("%s\n", res)
+}
+
+// renderTextParam is the text usage of one param.
+func renderTextParam(p UsageParam, indent int, style textStyle) string {
+	indentString := strings.Repeat("\t", indent)
+	res := indentString + "Param: " + style.title(p.Name.String())
+	append := func(s string) {
+		res += "\n" + indentString + "\t" + s
+	}
+	if len(p.Description) > 0 {
+		append("Description: " + p.Description)
+	}
+	if len(p.Examples) > 0 {
+		append(fmt.Sprintf("Example: %v", p.Examples))
+	}
+	if p.Default != "" {
+		append("Default: " + style.value(p.Default))
+	}
+	if len(p.Constraints.EnumValues) > 0 && p.Constraints.EnumCaseInsensitive {
+		append(fmt.Sprintf("EnumValues: %v (case insensitive)", p.Constraints.EnumValues))
+	} else if len(p.Constraints.EnumValues) > 0 {
+		append(fmt.Sprintf("EnumValues: %v", p.Constraints.EnumValues))
+	}
+	if len(p.Constraints.Validations) > 0 {
+		append("Validation: " + strings.Join(p.Constraints.Validations, ", "))
+	}
+	if p.Constraints.IsMandatory {
+		append("Mandatory value.")
+	}
+	if len(p.Constraints.Exclusive) > 0 {
+		append(fmt.Sprintf("Exclusive with: %v", p.Constraints.Exclusive))
+	}
+	if len(p.Constraints.Requires) > 0 {
+		append(fmt.Sprintf("Requires: %v", p.Constraints.Requires))
+	}
+	if len(p.Constraints.RequiredIf) > 0 {
+		append("Mandatory if: " + strings.Join(p.Constraints.RequiredIf, " or "))
+	}
+	if p.Constraints.IsSubCommandLocal {
+		append("This param won't be available in sub commands.")
+	}
+	if p.Sources.Priority != nil {
+		append("Source priority: " + source.FormatPriority(p.Sources.Priority))
+	}
+	if len(p.Sources.Flags) > 0 {
+		forms := p.Sources.Flags
+		if p.Sources.IsSwitch {
+			append("Command line flag: " + style.value(strings.Join(forms, ", ")) + " (switch, same as " + forms[0] + "=true)")
+		} else {
+			append("Command line flag: " + style.value(strings.Join(forms, ", ")))
+		}
+	} else {
+		append("Command line flag disable.")
+	}
+	if p.Sources.Separator != "" {
+		append(fmt.Sprintf("List of values, separated by %q. A repeated flag appends.", p.Sources.Separator))
+	}
+	if p.Sources.EnvVar != "" {
+		append("Environment variable name: " + style.value(p.Sources.EnvVar))
+	} else {
+		append("Environment variable disable.")
+	}
+	if p.Sources.Loader {
+		if p.Sources.LoaderFrequency == 0 {
+			append("Using a custom loader without periodic update.")
+		} else if p.Sources.LoaderAlwaysSync {
+			append("Using a custom loader, refresh every " + p.Sources.LoaderFrequency.String() + ", even when a higher priority source has a value")
+		} else {
+			append("Using a custom loader, refresh every " + p.Sources.LoaderFrequency.String())
+		}
+	} else {
+		append("No custom loader defined.")
+	}
+
+	return res + "\n"
+}
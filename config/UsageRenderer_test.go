@@ -0,0 +1,117 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/source"
+)
+
+func newUsageManager(t *testing.T) *Manager {
+	pRegion, err := param.New("Region", func(s string) error { return nil },
+		param.WithDesc("where to deploy"),
+		param.WithIsMandatory(true),
+		param.WithEnumValues("eu", "us"),
+		param.WithFlag(param.WithShortName('r')),
+		param.WithEnvVar(param.WithEnvVarName("APP_REGION")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pHost, err := param.New("Host", func(s string) error { return nil }, param.WithGroup("Database"), param.WithSourcePriority(source.EnvVar))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cDeploy, err := New(WithDescription("deploy the app"), WithArg("env"), WithAliases("dep"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(pRegion, pHost), WithSubCommand("deploy", cDeploy), WithFlagStyle(FlagStyleGNU), WithUsageWidth(-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestManager_UsageModel(t *testing.T) {
+	got := newUsageManager(t).UsageModel()
+
+	if len(got.Params) != 1 || len(got.Groups) != 1 || len(got.Commands) != 1 {
+		t.Fatalf("Manager.UsageModel() got =%+v", got)
+	}
+	region := got.Params[0]
+	if fmt.Sprintf("%q", region.Sources.Flags) != `["--Region" "-r"]` || region.Sources.EnvVar != "APP_REGION" ||
+		!region.Constraints.IsMandatory || fmt.Sprintf("%v", region.Constraints.EnumValues) != "[eu us]" {
+		t.Errorf("Manager.UsageModel() param Region got =%+v", region)
+	}
+	host := got.Groups[0].Params[0]
+	if got.Groups[0].Name != "Database" || len(host.Sources.Flags) != 0 || host.Sources.EnvVar != "Host" {
+		t.Errorf("Manager.UsageModel() param Host got =%+v", host)
+	}
+	deploy := got.Commands[0]
+	if fmt.Sprintf("%v", deploy.Path) != "[deploy]" || deploy.ArgsSynopsis != "<env>" || fmt.Sprintf("%v", deploy.Aliases) != "[dep]" {
+		t.Errorf("Manager.UsageModel() command deploy got =%+v", deploy)
+	}
+}
+
+func TestUsageRenderer(t *testing.T) {
+	c := newUsageManager(t)
+	tmpl, err := NewTemplateRenderer(`{{range .Params}}{{.Name}}: {{join ", " .Sources.Flags}}{{"\n"}}{{end}}{{range .Commands}}{{join " " .Path}} {{.ArgsSynopsis}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		renderer UsageRenderer
+		want     string
+	}{
+		{name: "text", renderer: TextRenderer{Width: -1}, want: c.Usage(0)},
+		{name: "color, plain when not a terminal", renderer: ColorRenderer{TextRenderer{Width: -1}}, want: c.Usage(0)},
+		{name: "template", renderer: tmpl, want: "Region: --Region, -r\ndeploy <env>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := tt.renderer.RenderUsage(&b, c.UsageModel()); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("RenderUsage()\ngot =%q\nwant=%q", b.String(), tt.want)
+			}
+		})
+	}
+
+	if _, err := NewTemplateRenderer("{{.Unknown"); err == nil {
+		t.Error("NewTemplateRenderer() expect error")
+	}
+}
+
+func Test_renderText_color(t *testing.T) {
+	got := renderText(newUsageManager(t).UsageModel(), 0, textStyleColor)
+	if !strings.Contains(got, "Param: "+ansiBold+"Region"+ansiReset) {
+		t.Errorf("renderText() got =%q", got)
+	}
+	if displayWidth(ansiBold+"ab"+ansiReset) != 2 {
+		t.Errorf("displayWidth() expect the colors ignored")
+	}
+}
+
+func TestManager_Init_helpRenderer(t *testing.T) {
+	tmpl, err := NewTemplateRenderer(`usage of {{join " " .Path}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newUsageManager(t)
+	c.UsageRenderer = tmpl
+	var out bytes.Buffer
+	if err := c.Init(context.Background(), WithInputArgs([]string{"deploy", "--help"}), WithOutput(&out), WithExit(func(int) {})); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "usage of deploy" {
+		t.Errorf("Init() help got =%q", out.String())
+	}
+}
@@ -1,12 +1,19 @@
 package config
 
 import (
+	"bytes"
 	"context"
+	stderrors "errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	"log/slog"
+
 	"github.com/vincentkerdraon/configo/config/errors"
 	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/paramname"
+	"github.com/vincentkerdraon/configo/config/param/source"
 	"github.com/vincentkerdraon/configo/config/subcommand"
 )
 
@@ -70,9 +77,10 @@ Usage:
 		Default: Vancouver
 		EnumValues: [Toronto Vancouver Montreal]
 		Mandatory value.
+		Exclusive with: [OtherName]
 		This param won't be available in sub commands.
 		Command line flag: -Town
-		Environment variable name: Town
+		Environment variable name: TOWN
 		No custom loader defined.
 `,
 		},
@@ -94,9 +102,10 @@ Config/Command description: A city reader
 		Default: Vancouver
 		EnumValues: [Toronto Vancouver Montreal]
 		Mandatory value.
+		Exclusive with: [OtherName]
 		This param won't be available in sub commands.
 		Command line flag: -Town
-		Environment variable name: Town
+		Environment variable name: TOWN
 		No custom loader defined.
 
 `,
@@ -129,9 +138,10 @@ Command: City
 			Default: Vancouver
 			EnumValues: [Toronto Vancouver Montreal]
 			Mandatory value.
+			Exclusive with: [OtherName]
 			This param won't be available in sub commands.
 			Command line flag: -Town
-			Environment variable name: Town
+			Environment variable name: TOWN
 			No custom loader defined.
 
 
@@ -147,6 +157,31 @@ Command: City
 	}
 }
 
+func TestConfig_usageWhenConfigError_sourcePriority(t *testing.T) {
+	pRegion, err := param.NewString("Region", func(string) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	cDeploy, err := New(WithParams(pRegion))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithSubCommand("deploy", cDeploy), WithSourcePriority(source.Flag, source.Default))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := []subcommand.SubCommand{subCommandLevel0, "deploy"}
+	for _, err := range []error{
+		errors.ParamConfigError{SubCommands: path, ParamName: "Region", Err: fmt.Errorf("err desc")},
+		errors.ConfigError{SubCommands: path, Err: fmt.Errorf("err desc")},
+	} {
+		got := c.usageWhenConfigError(err).Error()
+		if !strings.Contains(got, "Command line flag: -Region") || strings.Contains(got, "Environment variable name") {
+			t.Errorf("Config.usageWhenConfigError() inherits the source priority\ngot =%s", got)
+		}
+	}
+}
+
 func TestManagerUsage(t *testing.T) {
 	s1p1, err := param.New("p1", func(s string) error { return nil }, param.WithIsMandatory(true))
 	if err != nil {
@@ -212,6 +247,14 @@ func TestManagerUsage(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	s8p1, err := param.New("p1", func(s string) error { return nil }, param.WithIsMandatory(true), param.WithFlag(param.WithFlagName("param1"), param.WithShortName('p')))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s8, err := New(WithParams(s8p1), WithFlagStyle(FlagStyleGNU))
+	if err != nil {
+		t.Fatal(err)
+	}
 	_ = s1
 	_ = s2
 	_ = s3
@@ -286,9 +329,12 @@ func TestManagerUsage(t *testing.T) {
 			name: "5) unexpected subCommand",
 			cm:   s5,
 			args: []string{"unexpected"},
-			expectedErr: errors.ConfigError{
-				SubCommands: []subcommand.SubCommand{subCommandLevel0, "unexpected"},
-				Err:         fmt.Errorf("undefined command. Declared: []"),
+			expectedErr: errors.ConfigWithUsageError{
+				Err: errors.ConfigError{
+					SubCommands: []subcommand.SubCommand{subCommandLevel0, "unexpected"},
+					Err:         errors.SubCommandUnknownError{Name: "unexpected", Declared: []subcommand.SubCommand{}},
+				},
+				Usage: "\n\n\tParam: p1\n\t\tCommand line flag: -p1\n\t\tEnvironment variable name: p1\n\t\tNo custom loader defined.\n\n",
 			},
 		},
 		{
@@ -316,6 +362,19 @@ func TestManagerUsage(t *testing.T) {
 				Usage: "\n\n\tParam: p1\n\t\tCommand line flag: -p1\n\t\tEnvironment variable name: p1\n\t\tNo custom loader defined.\n\n",
 			},
 		},
+		{
+			name: "gnu flag style shows long and short names",
+			cm:   s8,
+			args: []string{"--param1="},
+			expectedErr: errors.ConfigWithUsageError{
+				Err: errors.ParamConfigError{
+					SubCommands: []subcommand.SubCommand{subCommandLevel0},
+					ParamName:   "p1",
+					Err:         errors.ErrMandatoryValue,
+				},
+				Usage: "\tParam: p1\n\t\tMandatory value.\n\t\tCommand line flag: --param1, -p\n\t\tEnvironment variable name: p1\n\t\tNo custom loader defined.\n",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -330,3 +389,208 @@ func TestManagerUsage(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_suggestions(t *testing.T) {
+	pRegion, err := param.New("Region", func(s string) error { return nil }, param.WithEnvVar(param.WithEnvVarName("APP_REGION")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cDeploy, err := New(WithAliases("dep"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cDebug, err := New(WithHidden(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(pRegion), WithSubCommand("deploy", cDeploy), WithSubCommand("debug", cDebug))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "flag typo", args: []string{"-Regoin=eu"}, want: []string{"-Region"}},
+		{name: "flag using the env var name", args: []string{"-APP_REGION=eu"}, want: []string{"-Region"}},
+		{name: "flag too different", args: []string{"-Zone=eu"}, want: []string{}},
+		{name: "subcommand typo, hidden not suggested", args: []string{"depoly"}, want: []string{"deploy"}},
+		{name: "subcommand alias typo", args: []string{"dpe"}, want: []string{"deploy"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.Init(context.Background(), WithInputArgs(tt.args))
+			cwue := errors.ConfigWithUsageError{}
+			if !stderrors.As(err, &cwue) {
+				t.Fatalf("Init() expect ConfigWithUsageError, got =%v", err)
+			}
+			if fmt.Sprintf("%q", cwue.Suggestions) != fmt.Sprintf("%q", tt.want) {
+				t.Errorf("Init() Suggestions\ngot =%q\nwant=%q", cwue.Suggestions, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_checkEnvVars(t *testing.T) {
+	var logs bytes.Buffer
+	pTimeout, err := param.New("Timeout", func(s string) error { return nil }, param.WithEnvVar(param.WithEnvVarName("MYAPP_TIMEOUT")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(pTimeout), WithEnvVarCheck("MYAPP_"), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.checkEnvVars(context.Background(), []string{"HOME=/root", "MYAPP_TIMEOUT=1s", "MYAPP_TIMEOUT_FILE=/tmp/t", "MYAPP_TIMOUT=2s"})
+	got := strings.TrimSpace(logs.String())
+	want := `msg="env var not matching any param" envVar=MYAPP_TIMOUT didYouMean=[MYAPP_TIMEOUT]`
+	if strings.Count(got, "\n") != 0 || !strings.HasSuffix(got, want) {
+		t.Errorf("checkEnvVars()\ngot =%s\nwant=%s", got, want)
+	}
+}
+
+func TestManager_usageOrder(t *testing.T) {
+	newParam := func(name paramname.ParamName, opt func(*param.Param) error) *param.Param {
+		p, err := param.New(name, func(s string) error { return nil }, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	cB, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cA, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(
+		WithParams(newParam("Zone", nil), newParam("Host", param.WithGroup("Database")), newParam("Debug", param.WithHidden()), newParam("Alpha", nil)),
+		WithSubCommand("b", cB),
+		WithSubCommand("a", cA),
+		WithUsageWidth(-1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, line := range strings.Split(c.Usage(0), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Param: ") || strings.HasPrefix(line, "Group: ") || strings.HasPrefix(line, "Command: ") {
+			names = append(names, line)
+		}
+	}
+	want := []string{"Param: Zone", "Param: Alpha", "Group: Database", "Param: Host", "Command: b", "Command: a"}
+	if fmt.Sprintf("%q", names) != fmt.Sprintf("%q", want) {
+		t.Errorf("Manager.Usage()\ngot =%q\nwant=%q", names, want)
+	}
+}
+
+func Test_wrapLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		{name: "no width", in: "aaa bbb ccc", width: 0, want: "aaa bbb ccc"},
+		{name: "short", in: "aaa bbb", width: 10, want: "aaa bbb"},
+		{name: "wrapped", in: "aaa bbb ccc", width: 8, want: "aaa bbb\n\tccc"},
+		{name: "indentation kept", in: "\taaa bbb ccc", width: 16, want: "\taaa bbb\n\t\tccc"},
+		{name: "long word", in: "aaaaaaaaaaaa", width: 4, want: "aaaaaaaaaaaa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapLines(tt.in, tt.width); got != tt.want {
+				t.Errorf("wrapLines()\ngot =%q\nwant=%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_Usage_width(t *testing.T) {
+	t.Setenv("COLUMNS", "20")
+	p, err := param.New("Region", func(s string) error { return nil }, param.WithDesc("the region where the app is deployed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Description: the region where the app is deployed"
+	if got := c.Usage(0); !strings.Contains(got, want) {
+		t.Errorf("default, no wrapping\ngot =%s\nwant=%q", got, want)
+	}
+	var out bytes.Buffer
+	if err := c.Init(context.Background(), WithInputArgs([]string{"-h"}), WithOutput(&out), WithExit(func(int) {})); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("help not to a terminal, no wrapping\ngot =%s\nwant=%q", out.String(), want)
+	}
+
+	c.UsageWidth = 30
+	if got := c.Usage(0); strings.Contains(got, want) {
+		t.Errorf("WithUsageWidth(30), expect wrapping\ngot =%s", got)
+	}
+}
+
+func TestManager_Init_help(t *testing.T) {
+	pRegion, err := param.New("Region", func(s string) error { return nil }, param.WithIsMandatory(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cDeploy, err := New(WithDescription("deploy the app"), WithArg("env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(pRegion), WithSubCommand("deploy", cDeploy), WithUsageWidth(-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "short", args: []string{"-h"}, want: "Param: Region"},
+		{name: "long", args: []string{"--help"}, want: "Param: Region"},
+		{name: "subcommand", args: []string{"deploy", "-help"}, want: "deploy the app"},
+		{name: "command", args: []string{"help", "deploy"}, want: "deploy the app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			code := -1
+			err := c.Init(context.Background(), WithInputArgs(tt.args), WithOutput(&out), WithExit(func(c int) { code = c }))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code != 0 || !strings.Contains(out.String(), tt.want) {
+				t.Errorf("Init() got code:%d output:%s", code, out.String())
+			}
+		})
+	}
+
+	//A param with the same name wins.
+	var got string
+	pHelp, err := param.New("help", func(s string) error { got = s; return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := New(WithParams(pHelp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c2.Init(context.Background(), WithInputArgs([]string{"-help=me"}), WithExit(func(int) { t.Error("Init() unexpected exit") })); err != nil {
+		t.Fatal(err)
+	}
+	if got != "me" {
+		t.Errorf("Init() param help got =%q", got)
+	}
+}
@@ -0,0 +1,11 @@
+package config
+
+import "github.com/vincentkerdraon/configo/config/param"
+
+// Value is a typed param value, safe for concurrent use without the lock. See param.NewTyped().
+type Value[T any] interface {
+	// Get returns the latest value. Lock-free.
+	Get() T
+}
+
+var _ Value[string] = (*param.Typed[string])(nil)
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/paramname"
+	"github.com/vincentkerdraon/configo/lock"
+)
+
+func TestManager_typedValue(t *testing.T) {
+	var loads atomic.Int32
+	p, v, err := param.NewTyped[int]("Count", param.WithLoader(
+		func(ctx context.Context) (string, error) {
+			if loads.Add(1) == 1 {
+				return "1", nil
+			}
+			return "2", nil
+		},
+		param.WithSynchroFrequency(time.Millisecond),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := lock.New()
+	c, err := New(WithParams(p), WithLock(l), WithLoadErrorHandler(func(_ paramname.ParamName, _ int, _ error) {}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.Init(ctx, WithInputArgs([]string{})); err != nil {
+		t.Fatal(err)
+	}
+	var count Value[int] = v
+	if count.Get() != 1 {
+		t.Errorf("after init\ngot =%d\nwant=%d", count.Get(), 1)
+	}
+
+	//The loader refresh doesn't need the Manager lock.
+	l.Lock()
+	defer l.Unlock()
+	deadline := time.Now().Add(time.Second)
+	for count.Get() != 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if count.Get() != 2 {
+		t.Errorf("after sync\ngot =%d\nwant=%d", count.Get(), 2)
+	}
+}
+
+func TestManager_syncConsecutiveErrNb(t *testing.T) {
+	var loads atomic.Int32
+	p, err := param.New("P", func(string) error { return nil }, param.WithLoader(
+		func(ctx context.Context) (string, error) {
+			//Init, then the sync: err, ok, err, ok...
+			switch loads.Add(1) {
+			case 2, 4:
+				return "", fmt.Errorf("err fetch")
+			}
+			return "v", nil
+		},
+		param.WithSynchroFrequency(time.Millisecond),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errNbs := make(chan int, 10)
+	c, err := New(WithParams(p), WithLoadErrorHandler(func(_ paramname.ParamName, consecutiveErrNb int, _ error) { errNbs <- consecutiveErrNb }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.Init(ctx, WithInputArgs([]string{})); err != nil {
+		t.Fatal(err)
+	}
+
+	//A success in between resets the count.
+	got := []int{}
+	for len(got) < 2 {
+		select {
+		case n := <-errNbs:
+			got = append(got, n)
+		case <-time.After(time.Second):
+			t.Fatalf("LoadErrorHandler not called\ngot =%v", got)
+		}
+	}
+	if got[0] != 1 || got[1] != 1 {
+		t.Errorf("consecutiveErrNb\ngot =%v\nwant=%v", got, []int{1, 1})
+	}
+}
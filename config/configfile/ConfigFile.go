@@ -0,0 +1,76 @@
+// Package configfile reads configuration files into flat keys, used as a source for the params.
+//
+// Nested keys are joined with a dot. For example JSON `{"db":{"host":"x"}}` or INI `[db] host=x` give the key `db.host`.
+// An array keeps its items, joined later with the separator of the param.
+// JSON and INI are built-in. Other formats can be added with Register().
+package configfile
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+type (
+	// Format is the file format, used to find the Decoder.
+	Format string
+
+	// Decoder reads a file content into flat keys. Nested keys are joined with a dot.
+	Decoder func(r io.Reader) (map[string]Value, error)
+
+	// Value is the value of a key. For an array, the items are in List.
+	Value struct {
+		Value string
+
+		//List are the items of an array, like JSON `["a","b"]`. See IsList.
+		List   []string
+		IsList bool
+	}
+)
+
+const (
+	JSON Format = "json"
+	INI  Format = "ini"
+)
+
+var (
+	decodersLock sync.RWMutex
+	decoders     = map[Format]Decoder{
+		JSON: DecodeJSON,
+		INI:  DecodeINI,
+	}
+)
+
+func (f Format) String() string {
+	return string(f)
+}
+
+// Register adds or replaces the Decoder for a format.
+func Register(f Format, d Decoder) {
+	decodersLock.Lock()
+	defer decodersLock.Unlock()
+	decoders[f] = d
+}
+
+// GetDecoder returns the Decoder registered for a format.
+func GetDecoder(f Format) (Decoder, bool) {
+	decodersLock.RLock()
+	defer decodersLock.RUnlock()
+	d, ok := decoders[f]
+	return d, ok
+}
+
+// Read opens the file and decodes it.
+func Read(path string, f Format) (map[string]Value, error) {
+	d, ok := GetDecoder(f)
+	if !ok {
+		return nil, fmt.Errorf("no decoder registered for format:%q", f)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return d(file)
+}
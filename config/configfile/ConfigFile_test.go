@@ -0,0 +1,57 @@
+package configfile
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	in := `{"Name":"Vincent","Age":35,"Debug":true,"Skip":null,"db":{"host":"localhost","port":5432},"peers":["a","b"]}`
+	got, err := DecodeJSON(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Value{
+		"Name":    {Value: "Vincent"},
+		"Age":     {Value: "35"},
+		"Debug":   {Value: "true"},
+		"db.host": {Value: "localhost"},
+		"db.port": {Value: "5432"},
+		"peers":   {List: []string{"a", "b"}, IsList: true},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("DecodeJSON\ngot =%v\nwant=%v", got, want)
+	}
+
+	if _, err := DecodeJSON(strings.NewReader(`{"peers":[{"a":1}]}`)); err == nil {
+		t.Errorf("DecodeJSON expect error for array of objects")
+	}
+}
+
+func TestDecodeINI(t *testing.T) {
+	in := `
+; comment
+# comment
+Name = Vincent
+Age: 35 ; inline comment
+
+[db]
+host = "local host"
+pass = 'a;b'
+`
+	got, err := DecodeINI(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Value{"Name": {Value: "Vincent"}, "Age": {Value: "35"}, "db.host": {Value: "local host"}, "db.pass": {Value: "a;b"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("DecodeINI\ngot =%v\nwant=%v", got, want)
+	}
+
+	for _, in := range []string{"[db", "novalue", `k="unclosed`} {
+		if _, err := DecodeINI(strings.NewReader(in)); err == nil {
+			t.Errorf("DecodeINI(%q) expect error", in)
+		}
+	}
+}
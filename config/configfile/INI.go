@@ -0,0 +1,66 @@
+package configfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DecodeINI reads a INI file. Keys in a section are prefixed with the section name and a dot.
+//
+// Supports `key=value` and `key: value`, comments starting with `;` or `#` and quoted values.
+func DecodeINI(r io.Reader) (map[string]Value, error) {
+	res := map[string]Value{}
+	var section string
+	scanner := bufio.NewScanner(r)
+	lineNb := 0
+	for scanner.Scan() {
+		lineNb++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: expect section `[name]`", lineNb)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i <= 0 {
+			return nil, fmt.Errorf("line %d: expect `key=value`", lineNb)
+		}
+		key := strings.TrimSpace(line[:i])
+		if section != "" {
+			key = section + "." + key
+		}
+		value, err := iniValue(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNb, err)
+		}
+		res[key] = Value{Value: value}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// iniValue removes the quotes, or the inline comment when not quoted.
+func iniValue(s string) (string, error) {
+	if len(s) > 0 && (s[0] == '"' || s[0] == '\'') {
+		end := strings.LastIndexByte(s, s[0])
+		if end == 0 {
+			return "", fmt.Errorf("missing closing quote")
+		}
+		return s[1:end], nil
+	}
+	for _, comment := range []string{" ;", " #", "\t;", "\t#"} {
+		if i := strings.Index(s, comment); i >= 0 {
+			s = s[:i]
+		}
+	}
+	return strings.TrimSpace(s), nil
+}
@@ -0,0 +1,69 @@
+package configfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// DecodeJSON reads a JSON object. Nested objects are joined with a dot.
+//
+// Arrays of values are kept as a list. null values are skipped.
+func DecodeJSON(r io.Reader) (map[string]Value, error) {
+	var in map[string]interface{}
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	if err := d.Decode(&in); err != nil {
+		return nil, err
+	}
+	res := map[string]Value{}
+	if err := flattenJSON(res, "", in); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func flattenJSON(res map[string]Value, prefix string, in map[string]interface{}) error {
+	for k, v := range in {
+		key := prefix + k
+		if obj, ok := v.(map[string]interface{}); ok {
+			if err := flattenJSON(res, key+".", obj); err != nil {
+				return err
+			}
+			continue
+		}
+		if arr, ok := v.([]interface{}); ok {
+			values := make([]string, 0, len(arr))
+			for _, item := range arr {
+				s, ok := jsonScalar(item)
+				if !ok {
+					return fmt.Errorf("key:%q, expect array of values", key)
+				}
+				values = append(values, s)
+			}
+			res[key] = Value{List: values, IsList: true}
+			continue
+		}
+		if v == nil {
+			continue
+		}
+		s, ok := jsonScalar(v)
+		if !ok {
+			return fmt.Errorf("key:%q, unexpected type %T", key, v)
+		}
+		res[key] = Value{Value: s}
+	}
+	return nil
+}
+
+func jsonScalar(v interface{}) (string, bool) {
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case json.Number:
+		return t.String(), true
+	case bool:
+		return fmt.Sprint(t), true
+	}
+	return "", false
+}
@@ -149,13 +149,14 @@ func Example_whenSubCommand() {
 	type User struct {
 		Name string
 	}
+	//The embedded User is ignored, already declared by the parent command.
 	type UserAndAge struct {
-		*User
-		Age int
+		*User `prefix:"-"`
+		Age   int
 	}
 	type UserAndCity struct {
-		*User
-		City string
+		*User `prefix:"-"`
+		City  string
 	}
 
 	user := User{}
@@ -349,17 +350,18 @@ func Example_whenMultipleSubCommand() {
 	type User struct {
 		Name string `mandatory:"true"`
 	}
+	//The embedded structs are ignored, already declared by the parent commands.
 	type UserAndAge struct {
-		*User
-		Age int `mandatory:"true"`
+		*User `prefix:"-"`
+		Age   int `mandatory:"true"`
 	}
 	type UserAndAgeAndCity struct {
-		*UserAndAge
-		City string `mandatory:"true"`
+		*UserAndAge `prefix:"-"`
+		City        string `mandatory:"true"`
 	}
 	type UserAndAgeAndCityAndJob struct {
-		*UserAndAgeAndCity
-		Job string `mandatory:"true"`
+		*UserAndAgeAndCity `prefix:"-"`
+		Job                string `mandatory:"true"`
 	}
 
 	user := User{}
@@ -0,0 +1,111 @@
+// Package dotenv reads `.env` files: one `KEY=VALUE` per line.
+//
+// Supports comments starting with `#`, the `export ` prefix, single quotes (literal) and double quotes (with escapes like `\n`).
+package dotenv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Read opens and decodes the files. The last file overrides the previous ones.
+//
+// Missing files are skipped, a `.env` file is usually only present when running locally.
+func Read(paths ...string) (map[string]string, error) {
+	res := map[string]string{}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values, err := Decode(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("file:%q, %w", path, err)
+		}
+		for k, v := range values {
+			res[k] = v
+		}
+	}
+	return res, nil
+}
+
+// Decode reads `KEY=VALUE` lines.
+func Decode(r io.Reader) (map[string]string, error) {
+	res := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	lineNb := 0
+	for scanner.Scan() {
+		lineNb++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		i := strings.IndexByte(line, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("line %d: expect `KEY=VALUE`", lineNb)
+		}
+		key := strings.TrimSpace(line[:i])
+		value, err := decodeValue(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNb, err)
+		}
+		res[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func decodeValue(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	switch s[0] {
+	case '\'':
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("missing closing quote")
+		}
+		return s[1 : end+1], nil
+	case '"':
+		var b strings.Builder
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '"':
+				return b.String(), nil
+			case '\\':
+				i++
+				if i == len(s) {
+					return "", fmt.Errorf("missing closing quote")
+				}
+				switch s[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(s[i])
+				}
+			default:
+				b.WriteByte(s[i])
+			}
+		}
+		return "", fmt.Errorf("missing closing quote")
+	}
+	//inline comment
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s), nil
+}
@@ -0,0 +1,54 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	in := `
+# comment
+NAME=Vincent
+export CITY = Vancouver # inline comment
+QUOTED="a \"b\"\nc # not a comment"
+LITERAL='a\nb'
+EMPTY=
+`
+	got, err := Decode(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"NAME": "Vincent", "CITY": "Vancouver", "QUOTED": "a \"b\"\nc # not a comment", "LITERAL": `a\nb`, "EMPTY": ""}
+	if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", want) {
+		t.Errorf("Decode\ngot =%q\nwant=%q", got, want)
+	}
+
+	for _, in := range []string{"NOVALUE", `K="unclosed`, `K='unclosed`, "=value"} {
+		if _, err := Decode(strings.NewReader(in)); err == nil {
+			t.Errorf("Decode(%q) expect error", in)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	p1 := filepath.Join(dir, "1.env")
+	p2 := filepath.Join(dir, "2.env")
+	if err := os.WriteFile(p1, []byte("A=1\nB=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p2, []byte("B=2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(p1, filepath.Join(dir, "missing.env"), p2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"A": "1", "B": "2"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Read\ngot =%v\nwant=%v", got, want)
+	}
+}
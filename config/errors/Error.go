@@ -3,9 +3,13 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/vincentkerdraon/configo/config/param/paramname"
 	"github.com/vincentkerdraon/configo/config/subcommand"
+	"github.com/vincentkerdraon/configo/secretrotation"
 )
 
 type ConfigAggregatedError struct {
@@ -74,9 +78,14 @@ func (err ConfigLoaderFetchError) Unwrap() error { return err.Err }
 type ConfigWithUsageError struct {
 	Err   error
 	Usage string
+	//Suggestions like the closest flags or subcommands, when unknown.
+	Suggestions []string
 }
 
 func (err ConfigWithUsageError) Error() string {
+	if len(err.Suggestions) > 0 {
+		return fmt.Sprintf("ConfigWithUsageError: %s\nDid you mean: %s?\nUsage:\n%s", err.Err, strings.Join(err.Suggestions, ", "), err.Usage)
+	}
 	return fmt.Sprintf("ConfigWithUsageError: %s\nUsage:\n%s", err.Err, err.Usage)
 }
 func (err ConfigWithUsageError) Unwrap() error { return err.Err }
@@ -90,14 +99,114 @@ func (err ParamParseError) Error() string {
 }
 func (err ParamParseError) Unwrap() error { return err.Err }
 
+// ParamValidationError is when the raw value fails a validator, see param.WithValidator().
+type ParamValidationError struct {
+	Err error
+}
+
+func (err ParamValidationError) Error() string {
+	return fmt.Sprintf("ParamValidationError: %s", err.Err)
+}
+func (err ParamValidationError) Unwrap() error { return err.Err }
+
+// SensitiveError hides the values of a sensitive param in the message of Err, see param.WithSensitive().
+//
+// Err is unchanged, for errors.Is() and errors.As().
+type SensitiveError struct {
+	Err error
+	//Redact scrubs the values in the message, like secretrotation.Secret.RedactSecret().
+	Redact func(s string) string
+}
+
+func (err SensitiveError) Error() string {
+	if err.Redact == nil {
+		return err.Err.Error()
+	}
+	return err.Redact(err.Err.Error())
+}
+func (err SensitiveError) Unwrap() error { return err.Err }
+
+// RedactValues replaces the values in s with secretrotation.SecretRedacted, also when written with %q. For SensitiveError.Redact.
+func RedactValues(s string, values ...string) string {
+	//The longest first, a value can contain another one. Like an item in a slice.
+	sorted := append([]string{}, values...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	for _, v := range sorted {
+		if v == "" {
+			continue
+		}
+		s = secretrotation.Secret(v).RedactSecret(s)
+		if quoted := strconv.Quote(v); quoted[1:len(quoted)-1] != v {
+			s = secretrotation.Secret(quoted[1 : len(quoted)-1]).RedactSecret(s)
+		}
+	}
+	return s
+}
+
 var ErrMandatoryValue = errors.New("mandatory value")
+var ErrConfigFileUnknownKey = errors.New("unknown key")
 var ErrLoaderFetch = errors.New("fail loader on fetch")
 
 type FlagUnknownError struct {
 	Err error
+	//Suggestions are the closest flags, see ConfigWithUsageError.
+	Suggestions []string
 }
 
 func (err FlagUnknownError) Error() string {
 	return fmt.Sprintf("FlagUnknownError: %s", err.Err)
 }
 func (err FlagUnknownError) Unwrap() error { return err.Err }
+
+type ConfigFileError struct {
+	Path string
+	Err  error
+}
+
+func (err ConfigFileError) Error() string {
+	return fmt.Sprintf("ConfigFileError for file:%q: %s", err.Path, err.Err)
+}
+func (err ConfigFileError) Unwrap() error { return err.Err }
+
+// ArgError is about a positional arg. Name is empty for too many args.
+type ArgError struct {
+	Name string
+	Err  error
+}
+
+func (err ArgError) Error() string {
+	if err.Name == "" {
+		return fmt.Sprintf("ArgError: %s", err.Err)
+	}
+	return fmt.Sprintf("ArgError for Arg:%q: %s", err.Name, err.Err)
+}
+func (err ArgError) Unwrap() error { return err.Err }
+
+// RunError is when the command Run, or a hook, fails. See config.WithRun().
+type RunError struct {
+	SubCommands []subcommand.SubCommand
+	Err         error
+}
+
+func (err RunError) Error() string {
+	var res string
+	if len(err.SubCommands) != 0 {
+		if !(len(err.SubCommands) == 1 && err.SubCommands[0] == "") {
+			res = fmt.Sprintf("on SubCommands: %v, ", err.SubCommands)
+		}
+	}
+	return fmt.Sprintf("%sRunError: %s", res, err.Err)
+}
+func (err RunError) Unwrap() error { return err.Err }
+
+// SubCommandUnknownError when the subcommand is not declared.
+type SubCommandUnknownError struct {
+	Name     string
+	Declared []subcommand.SubCommand
+	//Suggestions are the closest subcommands, see ConfigWithUsageError.
+	Suggestions []string
+}
+
+func (err SubCommandUnknownError) Error() string {
+	return fmt.Sprintf("undefined command:%q. Declared: %v", err.Name, err.Declared)
+}
@@ -0,0 +1,91 @@
+package param
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param/paramname"
+)
+
+type (
+	// enumValues is a Go type listing its allowed values, see EnumValuesOf().
+	enumValues interface {
+		Values() []string
+	}
+	// enumList is the same as enumValues, with another common name.
+	enumList interface {
+		List() []string
+	}
+)
+
+// EnumValuesOf returns the allowed values of a type implementing `Values() []string` or `List() []string`.
+// With a value or a pointer receiver, called on the zero value. For a pointer type, the pointed type.
+func EnumValuesOf(t reflect.Type) ([]string, bool) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil, false
+	}
+	v := reflect.New(t)
+	for _, candidate := range []reflect.Value{v.Elem(), v} {
+		if !candidate.CanInterface() {
+			continue
+		}
+		switch e := candidate.Interface().(type) {
+		case enumValues:
+			return e.Values(), true
+		case enumList:
+			return e.List(), true
+		}
+	}
+	return nil, false
+}
+
+// WithEnumCaseInsensitive matches the enum values ignoring the case, like `S3` for `s3`.
+// Parse receives the declared value.
+func WithEnumCaseInsensitive() paramOption {
+	return func(p *Param) error {
+		p.EnumCaseInsensitive = true
+		return nil
+	}
+}
+
+// EnumValue returns the declared enum value matching s. Always s when the param is not an enum.
+func (p Param) EnumValue(s string) (_ string, found bool) {
+	if len(p.EnumValues) == 0 {
+		return s, true
+	}
+	for _, v := range p.EnumValues {
+		if v == s || p.EnumCaseInsensitive && strings.EqualFold(v, s) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// NewEnum creates a Param for a string type listing its allowed values, with `Values() []string` or `List() []string`.
+//
+//	type Mode string
+//	func (Mode) Values() []string { return []string{"local", "s3"} }
+//	...
+//	param.NewEnum("Mode", func(m Mode) error { mode = m; return nil })
+func NewEnum[T ~string](
+	name paramname.ParamName,
+	parse func(T) error,
+	opts ...paramOption,
+) (*Param, error) {
+	t := typeOf[T]()
+	values, ok := EnumValuesOf(t)
+	if !ok {
+		return nil, errors.ParamConfigError{ParamName: name, Err: fmt.Errorf("type %s must implement Values() []string or List() []string", t)}
+	}
+	return New(name, func(s string) error {
+		if len(s) == 0 {
+			return nil
+		}
+		return parse(T(s))
+	}, append([]paramOption{WithType(t), WithEnumValues(values...)}, opts...)...)
+}
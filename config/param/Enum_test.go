@@ -0,0 +1,102 @@
+package param
+
+import (
+	"reflect"
+	"testing"
+)
+
+type enumMode string
+
+func (enumMode) Values() []string { return []string{"local", "s3"} }
+
+type enumLevel int
+
+func (*enumLevel) List() []string { return []string{"debug", "info"} }
+
+func TestEnumValuesOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		t      reflect.Type
+		want   []string
+		wantOk bool
+	}{
+		{name: "Values", t: typeOf[enumMode](), want: []string{"local", "s3"}, wantOk: true},
+		{name: "List pointer receiver", t: typeOf[enumLevel](), want: []string{"debug", "info"}, wantOk: true},
+		{name: "pointer", t: typeOf[*enumMode](), want: []string{"local", "s3"}, wantOk: true},
+		{name: "not an enum", t: typeOf[string]()},
+		{name: "nil", t: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := EnumValuesOf(tt.t)
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EnumValuesOf()\ngot =%v %t\nwant=%v %t", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestNewEnum(t *testing.T) {
+	var got enumMode
+	p, err := NewEnum("Mode", func(m enumMode) error { got = m; return nil }, WithEnumCaseInsensitive())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p.EnumValues, []string{"local", "s3"}) {
+		t.Errorf("EnumValues\ngot =%v\nwant=%v", p.EnumValues, []string{"local", "s3"})
+	}
+	if v, found := p.EnumValue("S3"); !found || v != "s3" {
+		t.Errorf("EnumValue(S3)\ngot =%q %t\nwant=%q %t", v, found, "s3", true)
+	}
+	if _, found := p.EnumValue("gcs"); found {
+		t.Errorf("EnumValue(gcs) expect not found")
+	}
+	if err := p.Parse("s3"); err != nil || got != "s3" {
+		t.Errorf("Parse\ngot =%q %v\nwant=%q", got, err, "s3")
+	}
+
+	type notEnum string
+	if _, err := NewEnum("Mode", func(m notEnum) error { return nil }); err == nil {
+		t.Errorf("expect error for a type without Values() or List()")
+	}
+}
+
+func TestEnum_fromType(t *testing.T) {
+	conf := &struct {
+		Mode  enumMode
+		Level enumLevel `enumValues:"debug"`
+	}{}
+	p, err := NewParamFromStructTag(conf, "Mode", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p.EnumValues, []string{"local", "s3"}) {
+		t.Errorf("struct tag EnumValues\ngot =%v\nwant=%v", p.EnumValues, []string{"local", "s3"})
+	}
+	p, err = NewParamFromStructTag(conf, "Level", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p.EnumValues, []string{"debug"}) {
+		t.Errorf("the tag wins\ngot =%v\nwant=%v", p.EnumValues, []string{"debug"})
+	}
+
+	confPtr := &struct {
+		Mode *enumMode
+	}{}
+	p, err = NewParamFromStructTag(confPtr, "Mode", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p.EnumValues, []string{"local", "s3"}) {
+		t.Errorf("pointer field EnumValues\ngot =%v\nwant=%v", p.EnumValues, []string{"local", "s3"})
+	}
+
+	p, _, err = NewTyped[enumMode]("Mode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p.EnumValues, []string{"local", "s3"}) {
+		t.Errorf("NewTyped EnumValues\ngot =%v\nwant=%v", p.EnumValues, []string{"local", "s3"})
+	}
+}
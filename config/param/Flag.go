@@ -2,6 +2,7 @@ package param
 
 import (
 	"fmt"
+	"unicode"
 
 	"github.com/vincentkerdraon/configo/config/errors"
 )
@@ -10,7 +11,9 @@ type (
 	Flag struct {
 		//Name set when different from param name.
 		Name string
-		Use  bool
+		//ShortName is a one letter alias, like `-v`. 0 when not set.
+		ShortName rune
+		Use       bool
 	}
 
 	flagOptions func(*Flag) error
@@ -29,6 +32,19 @@ func WithFlagName(name string) flagOptions {
 	}
 }
 
+// WithShortName adds a one letter alias, like `-v` for `--verbose`.
+//
+// With config.FlagStyleGNU, short switches can be bundled: `-vx`.
+func WithShortName(r rune) flagOptions {
+	return func(f *Flag) error {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return errors.ConfigError{Err: fmt.Errorf("flag short name must be a letter or a digit, got:%q", string(r))}
+		}
+		f.ShortName = r
+		return nil
+	}
+}
+
 // WithReadFlag to prevent the flag from being available. Require another mean to get the value.
 //
 // default: true
@@ -15,6 +15,10 @@ type (
 
 		// OnChanged callback is called when value changes
 		OnChanged func()
+
+		// AlwaysSync keeps the Loader synchronizing even when a higher priority source has a value.
+		// Once the Loader value changes, it overrides the other sources.
+		AlwaysSync bool
 	}
 
 	loaderOptions func(r *Loader) error
@@ -39,6 +43,17 @@ func WithCallbackOnChanged(f func()) loaderOptions {
 	}
 }
 
+// WithAlwaysSync keeps the Loader synchronizing even when a higher priority source (like an env var) gave the startup value.
+// Once the Loader value changes, it overrides the other sources.
+//
+// default:false
+func WithAlwaysSync(t bool) loaderOptions {
+	return func(l *Loader) error {
+		l.AlwaysSync = t
+		return nil
+	}
+}
+
 // WithLoader uses a function to fetch data in a local file, secret manager ...
 func WithLoader(getter GetterFunc, opts ...loaderOptions) paramOption {
 	return func(p *Param) error {
@@ -2,9 +2,11 @@ package param
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/vincentkerdraon/configo/config/errors"
 	"github.com/vincentkerdraon/configo/config/param/paramname"
+	"github.com/vincentkerdraon/configo/config/param/source"
 )
 
 type (
@@ -26,12 +28,48 @@ type (
 		Default           string
 		Exclusive         []paramname.ParamName
 		IsSubCommandLocal bool
+		IsSensitive       bool
+
+		//EnumCaseInsensitive matches the EnumValues ignoring the case, see WithEnumCaseInsensitive().
+		EnumCaseInsensitive bool
+
+		//Requires are the params that must have a value when this param has one, see WithRequires().
+		Requires []paramname.ParamName
+		//RequiredIf makes this param mandatory when another param has a value, see WithRequiredIf().
+		RequiredIf []Condition
+
+		//Group is the section of the usage where this param is shown, see WithGroup().
+		Group string
+		//IsHidden param, working but not shown in the usage, the documentation and the completion.
+		IsHidden bool
+
+		//Separator splits the value of a slice or map param, see WithSeparator().
+		Separator string
+
+		//Type is the Go type of the value, when known. Set by the typed helpers like NewBool() or the struct tags.
+		Type reflect.Type
+
+		//Validators check the raw value before Parse, see WithValidator().
+		Validators []Validator
+
+		//IsConcurrentSafe when Parse can be called without the Manager lock, like NewTyped().
+		IsConcurrentSafe bool
+
+		//SourcePriority is the reading order, highest priority first. Sources not listed are not read.
+		//When nil, uses the Manager priority.
+		SourcePriority []source.Source
 
 		//prefix is only for the construction. If provided, it is used in Name + Flag.Name + EnvVar.Name
 		prefix string
 	}
 
 	paramOption func(*Param) error
+
+	// Condition is a param having a value, see WithRequiredIf().
+	Condition struct {
+		Param paramname.ParamName
+		Value string
+	}
 )
 
 // New creates a new Param.
@@ -133,7 +171,32 @@ func WithExclusive(params ...paramname.ParamName) paramOption {
 	}
 }
 
+// WithRequires defines params that must have a value if this param is filled. Like a TLS cert requiring the TLS key.
+//
+// Can be called multiple times.
+func WithRequires(params ...paramname.ParamName) paramOption {
+	return func(p *Param) error {
+		p.Requires = append(p.Requires, params...)
+		return nil
+	}
+}
+
+// WithRequiredIf makes this param mandatory when the other param has this value. Like `-bucket` required when `-mode=s3`.
+//
+// Can be called multiple times, any matching condition makes it mandatory.
+func WithRequiredIf(other paramname.ParamName, value string) paramOption {
+	return func(p *Param) error {
+		if other == "" || value == "" {
+			return fmt.Errorf("mandatory param name and value for the condition")
+		}
+		p.RequiredIf = append(p.RequiredIf, Condition{Param: other, Value: value})
+		return nil
+	}
+}
+
 // WithEnumValues defines exactly the values that can be use. Anything else leads to an error.
+//
+// default: the values of the Go type when it implements `Values() []string` or `List() []string`, see EnumValuesOf(). (With the struct tags, NewTyped() and NewEnum())
 func WithEnumValues(s ...string) paramOption {
 	return func(p *Param) error {
 		p.EnumValues = s
@@ -149,3 +212,88 @@ func WithPrefix(s string) paramOption {
 		return nil
 	}
 }
+
+// WithSensitive hides the value, for example a password or an API key.
+// Redacted in the logs, the errors, the usage default and the snapshots. Same as the struct tag `sensitive:"true"`.
+func WithSensitive() paramOption {
+	return func(p *Param) error {
+		p.IsSensitive = true
+		return nil
+	}
+}
+
+// WithGroup shows the param in a section of the usage, like "Database". The groups are in declaration order, after the params without group.
+func WithGroup(name string) paramOption {
+	return func(p *Param) error {
+		p.Group = name
+		return nil
+	}
+}
+
+// WithHidden for a param working but not shown in the usage, the documentation and the completion.
+// Like a debug switch.
+func WithHidden() paramOption {
+	return func(p *Param) error {
+		p.IsHidden = true
+		return nil
+	}
+}
+
+// WithSourcePriority defines which sources are read and in which order, highest priority first.
+// Sources not listed are not read.
+//
+// default: the Manager priority, see config.WithSourcePriority()
+// example: `WithSourcePriority(source.Flag, source.Loader, source.EnvVar, source.Default)` when a secret manager must win against a stale env var.
+func WithSourcePriority(priority ...source.Source) paramOption {
+	return func(p *Param) error {
+		if err := source.CheckPriority(priority); err != nil {
+			return err
+		}
+		p.SourcePriority = priority
+		return nil
+	}
+}
+
+// WithSeparator splits the value of a slice or map param, like `a,b` or `k1=v1,k2=v2`.
+// A repeated flag appends, like `-peer=a -peer=b`. The arrays in the config files are joined with this separator.
+//
+// default: SeparatorDefault
+func WithSeparator(sep string) paramOption {
+	return func(p *Param) error {
+		if sep == "" {
+			return fmt.Errorf("separator can't be empty")
+		}
+		p.Separator = sep
+		return nil
+	}
+}
+
+// ListSeparator is the separator when the param is a slice or a map. Empty otherwise.
+func (p Param) ListSeparator() string {
+	if !isList(p.Type) {
+		return ""
+	}
+	if p.Separator == "" {
+		return SeparatorDefault
+	}
+	return p.Separator
+}
+
+// IsMap when the param is a map, with items like `k1=v1,k2=v2`.
+func (p Param) IsMap() bool {
+	t := p.Type
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t != nil && t.Kind() == reflect.Map
+}
+
+// WithType defines the Go type of the value. Used for the documentation, like the JSON schema.
+//
+// default: set by the typed helpers like NewBool() or the struct tags, unknown otherwise.
+func WithType(t reflect.Type) paramOption {
+	return func(p *Param) error {
+		p.Type = t
+		return nil
+	}
+}
@@ -1,7 +1,10 @@
 package param
 
 import (
+	"fmt"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/vincentkerdraon/configo/config/param/paramname"
@@ -23,7 +26,7 @@ func NewBool(
 			return err
 		}
 		return parse(b)
-	}, opts...)
+	}, append([]paramOption{WithType(reflect.TypeOf(false))}, opts...)...)
 }
 
 func NewInt(
@@ -40,7 +43,7 @@ func NewInt(
 			return err
 		}
 		return parse(i)
-	}, opts...)
+	}, append([]paramOption{WithType(reflect.TypeOf(int(0)))}, opts...)...)
 }
 
 func NewInt64(
@@ -57,7 +60,7 @@ func NewInt64(
 			return err
 		}
 		return parse(int64(i))
-	}, opts...)
+	}, append([]paramOption{WithType(reflect.TypeOf(int64(0)))}, opts...)...)
 }
 
 func NewUint(
@@ -74,7 +77,7 @@ func NewUint(
 			return err
 		}
 		return parse(uint(i))
-	}, opts...)
+	}, append([]paramOption{WithType(reflect.TypeOf(uint(0)))}, opts...)...)
 }
 
 func NewUint64(
@@ -91,7 +94,7 @@ func NewUint64(
 			return err
 		}
 		return parse(i)
-	}, opts...)
+	}, append([]paramOption{WithType(reflect.TypeOf(uint64(0)))}, opts...)...)
 }
 
 func NewFloat64(
@@ -108,7 +111,7 @@ func NewFloat64(
 			return err
 		}
 		return parse(f)
-	}, opts...)
+	}, append([]paramOption{WithType(reflect.TypeOf(float64(0)))}, opts...)...)
 }
 
 func NewDuration(
@@ -125,7 +128,7 @@ func NewDuration(
 			return err
 		}
 		return parse(d)
-	}, opts...)
+	}, append([]paramOption{WithType(reflect.TypeOf(time.Duration(0)))}, opts...)...)
 }
 
 func NewString(
@@ -133,5 +136,114 @@ func NewString(
 	parse func(s string) error,
 	opts ...paramOption,
 ) (*Param, error) {
-	return New(name, parse, opts...)
+	return New(name, parse, append([]paramOption{WithType(reflect.TypeOf(""))}, opts...)...)
+}
+
+// SeparatorDefault splits the values of a slice or map param.
+const SeparatorDefault = ","
+
+// NewStringSlice is a list of values, like `-peer=a,b` or `-peer=a -peer=b` or `PEER=a,b`. See WithSeparator().
+func NewStringSlice(
+	name paramname.ParamName,
+	parse func([]string) error,
+	opts ...paramOption,
+) (*Param, error) {
+	return newSlice(name, func(s string) (string, error) { return s, nil }, parse, opts)
+}
+
+func NewIntSlice(
+	name paramname.ParamName,
+	parse func([]int) error,
+	opts ...paramOption,
+) (*Param, error) {
+	return newSlice(name, strconv.Atoi, parse, opts)
+}
+
+func NewDurationSlice(
+	name paramname.ParamName,
+	parse func([]time.Duration) error,
+	opts ...paramOption,
+) (*Param, error) {
+	return newSlice(name, time.ParseDuration, parse, opts)
+}
+
+// NewStringMap is a list of key=value, like `-label=env=prod,team=core` or `-label=env=prod -label=team=core`. See WithSeparator().
+func NewStringMap(
+	name paramname.ParamName,
+	parse func(map[string]string) error,
+	opts ...paramOption,
+) (*Param, error) {
+	var p *Param
+	p, err := New(name, func(s string) error {
+		if len(s) == 0 {
+			return nil
+		}
+		res := map[string]string{}
+		for _, item := range SplitList(s, p.ListSeparator()) {
+			k, v, err := SplitKeyValue(item)
+			if err != nil {
+				return err
+			}
+			res[k] = v
+		}
+		return parse(res)
+	}, append([]paramOption{WithType(reflect.TypeOf(map[string]string{}))}, opts...)...)
+	return p, err
+}
+
+// newSlice splits the value with the separator, and parses every item.
+func newSlice[T any](
+	name paramname.ParamName,
+	parseItem func(string) (T, error),
+	parse func([]T) error,
+	opts []paramOption,
+) (*Param, error) {
+	var p *Param
+	p, err := New(name, func(s string) error {
+		if len(s) == 0 {
+			return nil
+		}
+		res := []T{}
+		for _, item := range SplitList(s, p.ListSeparator()) {
+			v, err := parseItem(item)
+			if err != nil {
+				return fmt.Errorf("item:%q, %w", item, err)
+			}
+			res = append(res, v)
+		}
+		return parse(res)
+	}, append([]paramOption{WithType(reflect.TypeOf([]T{}))}, opts...)...)
+	return p, err
+}
+
+// SplitList splits the value of a slice or map param. The spaces around the items are removed, the empty items skipped.
+func SplitList(s string, sep string) []string {
+	res := []string{}
+	for _, item := range strings.Split(s, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// SplitKeyValue splits a map item, like `env=prod`.
+func SplitKeyValue(item string) (string, string, error) {
+	k, v, ok := strings.Cut(item, "=")
+	if !ok || strings.TrimSpace(k) == "" {
+		return "", "", fmt.Errorf("item:%q, expect key=value", item)
+	}
+	return strings.TrimSpace(k), strings.TrimSpace(v), nil
+}
+
+// isList when the type is a slice or a map, except []byte. The value is then split, see WithSeparator().
+func isList(t reflect.Type) bool {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		//[]byte is a string
+		return false
+	}
+	return t.Kind() == reflect.Slice || t.Kind() == reflect.Map
 }
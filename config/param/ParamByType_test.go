@@ -93,3 +93,51 @@ func TestNewPlusSpecificType(t *testing.T) {
 		t.Errorf("\ngot =%v\nwant=%v", res, expected)
 	}
 }
+
+func TestNewSliceAndMap(t *testing.T) {
+	var res string
+	p, _ := NewStringSlice("name", func(s []string) error {
+		res = fmt.Sprintf("%T:%q", s, s)
+		return nil
+	})
+	if err := p.Parse("a, b,,c"); err != nil {
+		t.Error(err)
+	}
+	p, _ = NewIntSlice("name", func(i []int) error {
+		res = fmt.Sprintf("%s %T:%v", res, i, i)
+		return nil
+	}, WithSeparator(";"))
+	if err := p.Parse("1;2"); err != nil {
+		t.Error(err)
+	}
+	if err := p.Parse("1,2"); err == nil {
+		t.Error("expect error with the wrong separator")
+	}
+	p, _ = NewDurationSlice("name", func(d []time.Duration) error {
+		res = fmt.Sprintf("%s %T:%v", res, d, d)
+		return nil
+	})
+	if err := p.Parse("1s,2m"); err != nil {
+		t.Error(err)
+	}
+	p, _ = NewStringMap("name", func(m map[string]string) error {
+		res = fmt.Sprintf("%s %T:%v", res, m, m)
+		return nil
+	})
+	if err := p.Parse("env=prod, team=core"); err != nil {
+		t.Error(err)
+	}
+	if err := p.Parse("env"); err == nil {
+		t.Error("expect error for an item without value")
+	}
+	if err := p.Parse(""); err != nil {
+		t.Error(err)
+	}
+	want := `[]string:["a" "b" "c"] []int:[1 2] []time.Duration:[1s 2m0s] map[string]string:map[env:prod team:core]`
+	if res != want {
+		t.Errorf("\ngot =%s\nwant=%s", res, want)
+	}
+	if p.ListSeparator() != SeparatorDefault {
+		t.Errorf("ListSeparator() got =%q", p.ListSeparator())
+	}
+}
@@ -1,12 +1,14 @@
 package param
 
 import (
+	"encoding"
 	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/vincentkerdraon/configo/config/errors"
 	"github.com/vincentkerdraon/configo/config/param/paramname"
@@ -15,6 +17,7 @@ import (
 // StructTag* are the expected tags to decode for automatic configuration detection
 const (
 	StructTagFlag          = "flag"
+	StructTagShort         = "short"
 	StructTagEnvVar        = "envVar"
 	StructTagMandatory     = "mandatory"
 	StructTagDesc          = "desc"
@@ -22,24 +25,47 @@ const (
 	StructTagExamples      = "examples"
 	StructTagExclusiveTags = "exclusiveTags"
 	StructTagEnumValues    = "enumValues"
+	StructTagSep           = "sep"
+	StructTagPrefix        = "prefix"
+	StructTagMin           = "min"
+	StructTagMax           = "max"
+	StructTagPattern       = "pattern"
+	StructTagSensitive     = "sensitive"
 )
 
+// setter is the interface used by the std flag lib, see flag.Var()
+type setter interface {
+	Set(string) error
+}
+
+var setterType = reflect.TypeOf((*setter)(nil)).Elem()
+
+// decoderTypes are the interfaces of a struct decoding itself, so not a group of params. See isConfigStruct().
+var decoderTypes = []reflect.Type{
+	setterType,
+	reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem(),
+	reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem(),
+	reflect.TypeOf((*json.Unmarshaler)(nil)).Elem(),
+}
+
 // literalStore tries to set a value into a generic type. Best effort.
-func literalStore(s string, v reflect.Value) error {
+//
+// sep splits the value for a slice or a map, like `a,b` or `k1=v1,k2=v2`.
+func literalStore(s string, v reflect.Value, sep string) error {
 	//inspired by std json decode: func (d *decodeState) literalStore()
 	//heavily modified.
 
 	// use interface "Set(string) error" if defined. See std flag lib for flag.Var()
 	if v.CanInterface() {
-		type setter interface {
-			Set(string) error
-		}
-
 		if setter, ok := v.Interface().(setter); ok {
 			return setter.Set(s)
 		}
-
-		//TODO it would be great if we could detect non-pointer struct fulfilling the interface. I am giving up for now.
+		//A struct field fulfilling the interface with a pointer receiver.
+		if v.CanAddr() {
+			if setter, ok := v.Addr().Interface().(setter); ok {
+				return setter.Set(s)
+			}
+		}
 	}
 
 	switch v.Type().String() {
@@ -52,9 +78,16 @@ func literalStore(s string, v reflect.Value) error {
 		return nil
 	}
 
+	if isList(v.Type()) {
+		return literalStoreList(s, v, sep)
+	}
+
 	switch v.Kind() {
 	default:
 		val := reflect.ValueOf(s)
+		if !val.CanConvert(v.Type()) {
+			return &json.UnmarshalTypeError{Value: fmt.Sprintf("string:%q", s), Type: v.Type()}
+		}
 		v.Set(val.Convert(v.Type()))
 	case reflect.Interface:
 		f, err := strconv.ParseFloat(s, 64)
@@ -98,8 +131,43 @@ func literalStore(s string, v reflect.Value) error {
 	return nil
 }
 
+// literalStoreList replaces the slice or map with the split value. Every item is set with literalStore().
+func literalStoreList(s string, v reflect.Value, sep string) error {
+	items := SplitList(s, sep)
+	if v.Kind() == reflect.Slice {
+		res := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := literalStore(item, res.Index(i), sep); err != nil {
+				return err
+			}
+		}
+		v.Set(res)
+		return nil
+	}
+
+	if v.Type().Key().Kind() != reflect.String {
+		return &json.UnmarshalTypeError{Value: fmt.Sprintf("map:%q", s), Type: v.Type()}
+	}
+	res := reflect.MakeMapWithSize(v.Type(), len(items))
+	for _, item := range items {
+		k, val, err := SplitKeyValue(item)
+		if err != nil {
+			return err
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := literalStore(val, elem, sep); err != nil {
+			return err
+		}
+		res.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), elem)
+	}
+	v.Set(res)
+	return nil
+}
+
 // NewParamFromStructTag tries to automatically define a param using reflection on the struct.
 // If parse is null, it tries to do the matching (best effort).
+//
+// The name of a nested struct field is dotted, like `DB.Host`. See IterateStructFields().
 func NewParamFromStructTag(
 	v interface{},
 	name paramname.ParamName,
@@ -107,41 +175,66 @@ func NewParamFromStructTag(
 	opts ...paramOption,
 ) (*Param, error) {
 	paramName := paramname.ParamName(name)
-	field, ok := reflect.TypeOf(v).Elem().FieldByName(name.String())
+	field, _, path, ok := structField(reflect.ValueOf(v).Elem(), name.String())
 	if !ok {
 		return nil, errors.ParamConfigError{ParamName: paramName, Err: fmt.Errorf("fail find struct field:%q", name)}
 	}
 
+	sep := field.Tag.Get(StructTagSep)
+	if sep == "" {
+		sep = SeparatorDefault
+	}
 	if parse == nil && field.IsExported() {
 		parse = func(s string) error {
 			if s == "" {
 				return nil
 			}
 
-			structFieldValue := reflect.ValueOf(v).Elem().FieldByName(name.String())
-			if err := literalStore(s, structFieldValue); err != nil {
+			_, structFieldValue, _, _ := structField(reflect.ValueOf(v).Elem(), name.String())
+			if err := literalStore(s, structFieldValue, sep); err != nil {
 				return errors.ParamConfigError{ParamName: paramName, Err: err}
 			}
 			return nil
 		}
 	}
 
-	paramOptions := []paramOption{}
+	paramOptions := []paramOption{WithType(field.Type)}
+	if _, ok := field.Tag.Lookup(StructTagSep); ok {
+		paramOptions = append(paramOptions, WithSeparator(sep))
+	}
 
-	if alias, ok := field.Tag.Lookup(StructTagFlag); ok {
-		if alias == "-" {
-			paramOptions = append(paramOptions, WithFlag(WithReadFlag(false)))
-		} else if alias != "" {
-			paramOptions = append(paramOptions, WithFlag(WithFlagName(alias)))
-		}
+	//A nested field like `DB.Host` is `-db-host` and `DB_HOST`, unless the tags say otherwise.
+	flagPrefix, envVarPrefix := "", ""
+	for _, segment := range path {
+		flagPrefix += KebabCase(segment) + "-"
+		envVarPrefix += UpperSnakeCase(segment) + "_"
 	}
 
-	if alias, ok := field.Tag.Lookup(StructTagEnvVar); ok {
-		if alias == "-" {
-			paramOptions = append(paramOptions, WithEnvVar(WithReadEnvVar(false)))
-		} else if alias != "" {
-			paramOptions = append(paramOptions, WithEnvVar(WithEnvVarName(alias)))
+	flagOptions := []flagOptions{}
+	if alias, ok := field.Tag.Lookup(StructTagFlag); ok && alias == "-" {
+		flagOptions = append(flagOptions, WithReadFlag(false))
+	} else if ok && alias != "" {
+		flagOptions = append(flagOptions, WithFlagName(flagPrefix+alias))
+	} else if len(path) > 0 {
+		flagOptions = append(flagOptions, WithFlagName(flagPrefix+KebabCase(field.Name)))
+	}
+	if alias, ok := field.Tag.Lookup(StructTagShort); ok {
+		r := []rune(alias)
+		if len(r) != 1 {
+			return nil, errors.ParamConfigError{ParamName: paramName, Err: fmt.Errorf("struct tag:%q value must be 1 letter", StructTagShort)}
 		}
+		flagOptions = append(flagOptions, WithShortName(r[0]))
+	}
+	if len(flagOptions) > 0 {
+		paramOptions = append(paramOptions, WithFlag(flagOptions...))
+	}
+
+	if alias, ok := field.Tag.Lookup(StructTagEnvVar); ok && alias == "-" {
+		paramOptions = append(paramOptions, WithEnvVar(WithReadEnvVar(false)))
+	} else if ok && alias != "" {
+		paramOptions = append(paramOptions, WithEnvVar(WithEnvVarName(envVarPrefix+alias)))
+	} else if len(path) > 0 {
+		paramOptions = append(paramOptions, WithEnvVar(WithEnvVarName(envVarPrefix+UpperSnakeCase(field.Name))))
 	}
 
 	if alias, ok := field.Tag.Lookup(StructTagMandatory); ok {
@@ -152,6 +245,16 @@ func NewParamFromStructTag(
 		paramOptions = append(paramOptions, WithIsMandatory(b))
 	}
 
+	if alias, ok := field.Tag.Lookup(StructTagSensitive); ok {
+		b, err := strconv.ParseBool(alias)
+		if err != nil {
+			return nil, errors.ParamConfigError{ParamName: paramName, Err: fmt.Errorf("struct tag:%q value must be boolean", StructTagSensitive)}
+		}
+		if b {
+			paramOptions = append(paramOptions, WithSensitive())
+		}
+	}
+
 	if alias, ok := field.Tag.Lookup(StructTagDesc); ok {
 		paramOptions = append(paramOptions, WithDesc(alias))
 	}
@@ -174,9 +277,47 @@ func NewParamFromStructTag(
 
 	if alias, ok := field.Tag.Lookup(StructTagEnumValues); ok {
 		paramOptions = append(paramOptions, WithEnumValues(strings.Split(alias, ";")...))
+	} else if values, ok := EnumValuesOf(field.Type); ok {
+		paramOptions = append(paramOptions, WithEnumValues(values...))
+	}
+
+	//min and max are the length for a string, the value otherwise.
+	//For a slice or a map, about each value.
+	valueType := field.Type
+	if isList(valueType) {
+		valueType = valueType.Elem()
+	}
+	isText := valueType.Kind() == reflect.String && !valueType.Implements(setterType) && !reflect.PointerTo(valueType).Implements(setterType)
+	minTag, hasMin := field.Tag.Lookup(StructTagMin)
+	maxTag, hasMax := field.Tag.Lookup(StructTagMax)
+	if isText && (hasMin || hasMax) {
+		min, max := 0, -1
+		var err error
+		if hasMin {
+			if min, err = strconv.Atoi(minTag); err != nil {
+				return nil, errors.ParamConfigError{ParamName: paramName, Err: fmt.Errorf("struct tag:%q value must be an integer for a string", StructTagMin)}
+			}
+		}
+		if hasMax {
+			if max, err = strconv.Atoi(maxTag); err != nil {
+				return nil, errors.ParamConfigError{ParamName: paramName, Err: fmt.Errorf("struct tag:%q value must be an integer for a string", StructTagMax)}
+			}
+		}
+		paramOptions = append(paramOptions, WithLength(min, max))
+	} else {
+		if hasMin {
+			paramOptions = append(paramOptions, WithMin(minTag))
+		}
+		if hasMax {
+			paramOptions = append(paramOptions, WithMax(maxTag))
+		}
+	}
+
+	if alias, ok := field.Tag.Lookup(StructTagPattern); ok {
+		paramOptions = append(paramOptions, WithPattern(alias))
 	}
 
-	return New(paramname.ParamName(field.Name), parse, append(paramOptions, opts...)...)
+	return New(name, parse, append(paramOptions, opts...)...)
 }
 
 // ParamsFromStructTag reads the struct tags, using all the default options otherwise.
@@ -203,7 +344,9 @@ func ParamsFromStructTag(
 // IterateStructFields finds all the exported fields in a *struct.
 //
 // Input MUST be a pointer to the struct. To avoid `reflect: Elem of invalid type`.
-// This ignores all the struct fields. You have to make explicit.
+// The nested structs are read recursively, with a dotted name like `DB.Host`. The tag `prefix` replaces the field name in front.
+// The embedded structs are flattened, their fields are read without prefix. Ignored with the tag `prefix:"-"`, like a subcommand config embedding the parent config.
+// Only the plain config structs are nested, see isConfigStruct(). The nil pointers to struct are allocated.
 // This is mostly a helper to call NewParamFromStructTag on every fields with some added logic like a name prefix.
 func IterateStructFields(
 	v interface{},
@@ -215,25 +358,192 @@ func IterateStructFields(
 		//easier to find what the faulty struct.
 		panic(fmt.Errorf("expect Ptr, got %s", typeOfV.Kind()))
 	}
+	return iterateStructFields(reflect.ValueOf(v).Elem(), "", f)
+}
 
-	//Return nice error in case of double pointer
-	// if reflect.TypeOf(typeOfV).Kind() == reflect.Ptr {
-	// 	return fmt.Errorf("expect Ptr, got double pointer")
-	// }
-
-	st := typeOfV.Elem()
+func iterateStructFields(v reflect.Value, prefix string, f func(name paramname.ParamName) error) error {
+	st := v.Type()
 	for i := 0; i < st.NumField(); i++ {
 		field := st.Field(i)
-		//ignore embedded struct
-		if field.Type.Kind() == reflect.Struct {
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		t := structType(field.Type)
+		if t != nil && !isConfigStruct(t) && !reflect.PointerTo(t).Implements(setterType) {
+			//Like time.Time or sync.Mutex, no way to read it.
+			continue
+		}
+		if field.Tag.Get(StructTagPrefix) == "-" && t != nil {
+			//Like a subcommand config embedding the parent config, already declaring these params.
+			continue
+		}
+		if nested := nestedStruct(field, v.Field(i)); nested.IsValid() {
+			p := prefix
+			if segment := structSegment(field); segment != "" {
+				p += segment + "."
+			}
+			if err := iterateStructFields(nested, p, f); err != nil {
+				return err
+			}
 			continue
 		}
 		if !field.IsExported() {
 			continue
 		}
-		if err := f(paramname.ParamName(field.Name)); err != nil {
+		if err := f(paramname.ParamName(prefix + field.Name)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// nestedStruct is the struct to read recursively, or an invalid Value for a param.
+//
+// A struct (or a pointer to a struct) is a param when it has a method `Set(string) error`. The nil pointer is allocated.
+func nestedStruct(field reflect.StructField, v reflect.Value) reflect.Value {
+	t := structType(field.Type)
+	if t == nil || !isConfigStruct(t) {
+		return reflect.Value{}
+	}
+	if field.Type.Kind() != reflect.Ptr {
+		return v
+	}
+	if v.IsNil() {
+		if !v.CanSet() {
+			//Embedded pointer to a non exported struct
+			return reflect.Value{}
+		}
+		v.Set(reflect.New(t))
+	}
+	return v.Elem()
+}
+
+// structType is the struct type, also behind a pointer. nil otherwise.
+func structType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// isConfigStruct when the struct is only a group of params, to read recursively.
+//
+// Not when it decodes itself (like time.Time) or has no exported field (like sync.Mutex).
+func isConfigStruct(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	for _, decoder := range decoderTypes {
+		if pt.Implements(decoder) {
+			return false
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// structSegment is the name in front of the nested fields: the tag `prefix`, or the field name. Empty for a flattened embedded struct.
+func structSegment(field reflect.StructField) string {
+	if prefix, ok := field.Tag.Lookup(StructTagPrefix); ok {
+		return prefix
+	}
+	if field.Anonymous {
+		return ""
+	}
+	return field.Name
+}
+
+// structField finds the field by name, dotted for a nested struct like `DB.Host`. See IterateStructFields().
+//
+// path are the nested structs names, like `DB`.
+func structField(v reflect.Value, name string) (_ reflect.StructField, _ reflect.Value, path []string, found bool) {
+	segments := strings.Split(name, ".")
+	for _, segment := range segments[:len(segments)-1] {
+		field, nested, ok := findStructField(v, func(field reflect.StructField, nested reflect.Value) bool {
+			if !nested.IsValid() {
+				return false
+			}
+			return structSegment(field) == segment
+		})
+		if !ok {
+			return reflect.StructField{}, reflect.Value{}, nil, false
+		}
+		v = nestedStruct(field, nested)
+		path = append(path, segment)
+	}
+	leaf := segments[len(segments)-1]
+	field, fieldValue, ok := findStructField(v, func(field reflect.StructField, nested reflect.Value) bool {
+		return !nested.IsValid() && field.Name == leaf && field.IsExported()
+	})
+	return field, fieldValue, path, ok
+}
+
+// findStructField finds the first field matching, also in the embedded structs without prefix.
+//
+// nested is the nested struct value, invalid for a param. See nestedStruct().
+func findStructField(v reflect.Value, match func(field reflect.StructField, nested reflect.Value) bool) (reflect.StructField, reflect.Value, bool) {
+	st := v.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		nested := nestedStruct(field, v.Field(i))
+		if match(field, nested) {
+			return field, v.Field(i), true
+		}
+	}
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if !field.Anonymous || structSegment(field) != "" {
+			continue
+		}
+		if nested := nestedStruct(field, v.Field(i)); nested.IsValid() {
+			if f, fv, ok := findStructField(nested, match); ok {
+				return f, fv, true
+			}
+		}
+	}
+	return reflect.StructField{}, reflect.Value{}, false
+}
+
+// KebabCase is a flag name, like `max-conns` for `MaxConns`, `http` for `HTTP` or `db-host` for `DB.Host`.
+func KebabCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "-"))
+}
+
+// UpperSnakeCase is an env var name, like `MAX_CONNS` for `MaxConns` or `DB_HOST` for `DB.Host`.
+func UpperSnakeCase(s string) string {
+	return strings.ToUpper(strings.Join(splitWords(s), "_"))
+}
+
+// splitWords splits a Go name, like `HTTPServer` in `HTTP` `Server`. Also on `-`, `_` and `.`
+func splitWords(s string) []string {
+	res := []string{}
+	current := []rune{}
+	runes := []rune(s)
+	for i, r := range runes {
+		if r == '-' || r == '_' || r == '.' || r == ' ' {
+			if len(current) > 0 {
+				res = append(res, string(current))
+			}
+			current = []rune{}
+			continue
+		}
+		if i > 0 && len(current) > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				res = append(res, string(current))
+				current = []rune{}
+			}
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		res = append(res, string(current))
+	}
+	return res
+}
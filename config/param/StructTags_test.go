@@ -3,6 +3,7 @@ package param
 import (
 	"fmt"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -45,6 +46,7 @@ func TestNewParamFromStructTag(t *testing.T) {
 				Exclusive:   []paramname.ParamName{"ExampleS"},
 				EnumValues:  []string{"aa", "bb", "cc", "dd"},
 				Default:     "aa",
+				Type:        reflect.TypeOf(""),
 			},
 			check: func(i struct1, err error) error {
 				if err != nil {
@@ -95,6 +97,8 @@ func TestNewParamFromStructTag_parse(t *testing.T) {
 		KeyDuration time.Duration
 		ISetter     interfaceWithSetter
 		ISetterP    *interfaceWithSetter
+		KeySlice    []int
+		KeyMap      map[string]time.Duration `sep:";"`
 	}
 
 	myStruct1 := &struct1{
@@ -250,6 +254,50 @@ func TestNewParamFromStructTag_parse(t *testing.T) {
 			val:          "err",
 			wantErrParse: true,
 		},
+		{
+			name: "parse auto: slice",
+			args: args{
+				name: "KeySlice",
+				i:    myStruct1,
+			},
+			val: "1, 2,3",
+			check: func(t *testing.T, i *struct1) {
+				if fmt.Sprint(i.KeySlice) != "[1 2 3]" {
+					t.Errorf("got =%v\n", i.KeySlice)
+				}
+			},
+		},
+		{
+			name: "parse auto: slice err",
+			args: args{
+				name: "KeySlice",
+				i:    myStruct1,
+			},
+			val:          "1,a",
+			wantErrParse: true,
+		},
+		{
+			name: "parse auto: map with sep",
+			args: args{
+				name: "KeyMap",
+				i:    myStruct1,
+			},
+			val: "read=1s;write=2m",
+			check: func(t *testing.T, i *struct1) {
+				if fmt.Sprint(i.KeyMap) != "map[read:1s write:2m0s]" {
+					t.Errorf("got =%v\n", i.KeyMap)
+				}
+			},
+		},
+		{
+			name: "parse auto: map err",
+			args: args{
+				name: "KeyMap",
+				i:    myStruct1,
+			},
+			val:          "read",
+			wantErrParse: true,
+		},
 		// -- Won't work.
 		// {
 		// 	name: "interfaceWithSetter",
@@ -302,6 +350,21 @@ func TestIterateStructFields(t *testing.T) {
 		Simple
 		C string
 		NonExported
+		Since time.Time
+		Mu    sync.Mutex
+	}
+	type Embedding struct {
+		*Simple
+		C string
+	}
+	type EmbeddingIgnored struct {
+		*Simple `prefix:"-"`
+		C       string
+	}
+	type Nested struct {
+		DB     *Simple `prefix:"db"`
+		Simple `prefix:"Common"`
+		Setter interfaceWithSetter
 	}
 
 	type args struct {
@@ -362,9 +425,41 @@ func TestIterateStructFields(t *testing.T) {
 					return nil
 				},
 			},
-			// namesExpected: []string{"Empty", "D", "Simple", "C", "NonExported"},
+			namesExpected: []paramname.ParamName{"D.A", "D.B", "A", "B", "C"},
+		},
+		{
+			name: "Embedded without prefix tag",
+			args: args{
+				v: &Embedding{},
+				f: func(name paramname.ParamName) error {
+					namesReceived = append(namesReceived, name)
+					return nil
+				},
+			},
+			namesExpected: []paramname.ParamName{"A", "B", "C"},
+		},
+		{
+			name: "Embedded ignored",
+			args: args{
+				v: &EmbeddingIgnored{},
+				f: func(name paramname.ParamName) error {
+					namesReceived = append(namesReceived, name)
+					return nil
+				},
+			},
 			namesExpected: []paramname.ParamName{"C"},
 		},
+		{
+			name: "Nested pointer and prefix",
+			args: args{
+				v: &Nested{},
+				f: func(name paramname.ParamName) error {
+					namesReceived = append(namesReceived, name)
+					return nil
+				},
+			},
+			namesExpected: []paramname.ParamName{"db.A", "db.B", "Common.A", "Common.B", "Setter"},
+		},
 	}
 	for _, tt := range tests {
 		namesReceived = []paramname.ParamName{}
@@ -379,3 +474,116 @@ func TestIterateStructFields(t *testing.T) {
 		})
 	}
 }
+
+func TestNewParamFromStructTag_nested(t *testing.T) {
+	type DBConfig struct {
+		Host     string
+		MaxConns int    `flag:"max" envVar:"MAX"`
+		Password string `flag:"-"`
+	}
+	type Common struct {
+		Verbose bool
+	}
+	type Config struct {
+		Common
+		DB   *DBConfig
+		HTTP DBConfig `prefix:"web"`
+	}
+	conf := &Config{}
+	params, err := ParamsFromStructTag(conf, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, p := range params {
+		got = append(got, fmt.Sprintf("%s %s %s %t", p.Name, p.Flag.Name, p.EnvVar.Name, p.Flag.Use))
+		if err := p.Parse("3"); err != nil && p.Name != "Verbose" {
+			t.Fatal(err)
+		}
+	}
+	want := []string{
+		"Verbose   true",
+		"DB.Host db-host DB_HOST true",
+		"DB.MaxConns db-max DB_MAX true",
+		"DB.Password  DB_PASSWORD false",
+		"web.Host web-host WEB_HOST true",
+		"web.MaxConns web-max WEB_MAX true",
+		"web.Password  WEB_PASSWORD false",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParamsFromStructTag()\ngot =%q\nwant=%q", got, want)
+	}
+	if conf.DB == nil || conf.DB.Host != "3" || conf.DB.MaxConns != 3 || conf.HTTP.Password != "3" {
+		t.Errorf("ParamsFromStructTag() parse got =%+v %+v", conf.DB, conf.HTTP)
+	}
+}
+
+func Test_splitWords(t *testing.T) {
+	tests := []struct {
+		in        string
+		wantKebab string
+		wantSnake string
+	}{
+		{in: "Host", wantKebab: "host", wantSnake: "HOST"},
+		{in: "MaxConns", wantKebab: "max-conns", wantSnake: "MAX_CONNS"},
+		{in: "HTTPServer", wantKebab: "http-server", wantSnake: "HTTP_SERVER"},
+		{in: "DB", wantKebab: "db", wantSnake: "DB"},
+		{in: "ipV4Addr", wantKebab: "ip-v4-addr", wantSnake: "IP_V4_ADDR"},
+		{in: "max_conns", wantKebab: "max-conns", wantSnake: "MAX_CONNS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := KebabCase(tt.in); got != tt.wantKebab {
+				t.Errorf("KebabCase()\ngot =%s\nwant=%s", got, tt.wantKebab)
+			}
+			if got := UpperSnakeCase(tt.in); got != tt.wantSnake {
+				t.Errorf("UpperSnakeCase()\ngot =%s\nwant=%s", got, tt.wantSnake)
+			}
+		})
+	}
+}
+
+func TestNewParamFromStructTag_validation(t *testing.T) {
+	conf := &struct {
+		Port    int           `min:"1" max:"65535"`
+		Timeout time.Duration `min:"1s"`
+		Name    string        `min:"2" max:"5" pattern:"^[a-z]+$"`
+		Tags    []string      `max:"3"`
+		Bad     string        `min:"abc"`
+	}{}
+	tests := []struct {
+		name      string
+		value     string
+		wantDescs []string
+		wantErr   bool
+	}{
+		{name: "Port", value: "8080", wantDescs: []string{"min:1", "max:65535"}},
+		{name: "Port", value: "0", wantDescs: []string{"min:1", "max:65535"}, wantErr: true},
+		{name: "Timeout", value: "10ms", wantDescs: []string{"min:1s"}, wantErr: true},
+		{name: "Name", value: "abc", wantDescs: []string{"length:2..5", "pattern:^[a-z]+$"}},
+		{name: "Name", value: "abcdef", wantDescs: []string{"length:2..5", "pattern:^[a-z]+$"}, wantErr: true},
+		{name: "Name", value: "AB", wantDescs: []string{"length:2..5", "pattern:^[a-z]+$"}, wantErr: true},
+		{name: "Tags", value: "a,bcde", wantDescs: []string{"length:0..3"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.value, func(t *testing.T) {
+			p, err := NewParamFromStructTag(conf, paramname.ParamName(tt.name), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			descs := []string{}
+			for _, v := range p.Validators {
+				descs = append(descs, v.Desc)
+			}
+			if !reflect.DeepEqual(descs, tt.wantDescs) {
+				t.Errorf("validators\ngot =%q\nwant=%q", descs, tt.wantDescs)
+			}
+			if err := p.Validate(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := NewParamFromStructTag(conf, "Bad", nil); err == nil {
+		t.Errorf("expect error for an invalid min")
+	}
+}
@@ -0,0 +1,156 @@
+package param
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param/paramname"
+)
+
+// Typed holds the last parsed value of a param created with NewTyped().
+//
+// Safe for concurrent use, without the Manager lock. Implements config.Value[T].
+type Typed[T any] struct {
+	v atomic.Pointer[T]
+}
+
+// Get returns the latest parsed value. Lock-free.
+//
+// The zero value until the param is parsed.
+func (t *Typed[T]) Get() T {
+	if v := t.v.Load(); v != nil {
+		return *v
+	}
+	var zero T
+	return zero
+}
+
+// parsers are the parse functions registered by type, see RegisterParser().
+var parsers = struct {
+	sync.RWMutex
+	m map[reflect.Type]func(s string) (any, error)
+}{m: map[reflect.Type]func(s string) (any, error){}}
+
+// RegisterParser defines how NewTyped() parses the values of type T. Replaces the parser already registered for T.
+//
+// Without parser registered, the basic types, time.Duration, slices, maps and types implementing `Set(string) error` are supported.
+func RegisterParser[T any](parse func(s string) (T, error)) {
+	parsers.Lock()
+	defer parsers.Unlock()
+	parsers.m[typeOf[T]()] = func(s string) (any, error) { return parse(s) }
+}
+
+// NewTyped creates a Param and the handle to read its value, without callback and without lock.
+//
+// The value is parsed with the parser registered for T, see RegisterParser().
+// The value is swapped atomically, also when a Loader refreshes it.
+//
+//	p, timeout, err := param.NewTyped[time.Duration]("Timeout")
+//	...
+//	timeout.Get()
+func NewTyped[T any](
+	name paramname.ParamName,
+	opts ...paramOption,
+) (*Param, *Typed[T], error) {
+	t := typeOf[T]()
+	parse, err := parser[T](t)
+	if err != nil {
+		return nil, nil, errors.ParamConfigError{ParamName: name, Err: err}
+	}
+	res := &Typed[T]{}
+	typeOpts := []paramOption{WithType(t), withConcurrentSafe()}
+	if values, ok := EnumValuesOf(t); ok {
+		typeOpts = append(typeOpts, WithEnumValues(values...))
+	}
+	var p *Param
+	p, err = New(name, func(s string) error {
+		if len(s) == 0 {
+			return nil
+		}
+		v, err := parse(s, p.ListSeparator())
+		if err != nil {
+			return err
+		}
+		res.v.Store(&v)
+		return nil
+	}, append(typeOpts, opts...)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, res, nil
+}
+
+// withConcurrentSafe when Parse doesn't need the Manager lock.
+func withConcurrentSafe() paramOption {
+	return func(p *Param) error {
+		p.IsConcurrentSafe = true
+		return nil
+	}
+}
+
+// parser is the registered parser for T, or the same conversion as the struct tags.
+func parser[T any](t reflect.Type) (func(s string, sep string) (T, error), error) {
+	parsers.RLock()
+	registered, ok := parsers.m[t]
+	parsers.RUnlock()
+	if ok {
+		return func(s string, _ string) (T, error) {
+			v, err := registered(s)
+			if err != nil {
+				var zero T
+				return zero, err
+			}
+			return v.(T), nil
+		}, nil
+	}
+	if !literalStoreSupported(t) {
+		return nil, fmt.Errorf("no parser registered for type %s, see RegisterParser()", t)
+	}
+	return func(s string, sep string) (T, error) {
+		var v T
+		rv := reflect.ValueOf(&v).Elem()
+		if rv.Kind() == reflect.Pointer {
+			rv.Set(reflect.New(t.Elem()))
+		}
+		if err := literalStore(s, rv, sep); err != nil {
+			return v, err
+		}
+		return v, nil
+	}, nil
+}
+
+// literalStoreSupported when literalStore() knows how to set this type.
+func literalStoreSupported(t reflect.Type) bool {
+	if t.Implements(setterType) || reflect.PointerTo(t).Implements(setterType) {
+		return true
+	}
+	if t.Kind() == reflect.Pointer {
+		return false
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		//[]byte is a string
+		return true
+	}
+	if isList(t) {
+		if t.Kind() == reflect.Map && t.Key().Kind() != reflect.String {
+			return false
+		}
+		return literalStoreSupported(t.Elem())
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// typeOf T, also for an interface.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,92 @@
+package param
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type typedLevel int
+
+func (l *typedLevel) Set(s string) error {
+	switch s {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return fmt.Errorf("unknown level %q", s)
+	}
+	return nil
+}
+
+func typedParse[T any](t *testing.T, in string, opts ...paramOption) (T, error) {
+	t.Helper()
+	p, v, err := NewTyped[T]("name", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Parse(in)
+	return v.Get(), err
+}
+
+func TestNewTyped(t *testing.T) {
+	if got, err := typedParse[time.Duration](t, "3s"); err != nil || got != 3*time.Second {
+		t.Errorf("duration\ngot =%v, %v\nwant=%v", got, err, 3*time.Second)
+	}
+	if got, err := typedParse[int](t, "12"); err != nil || got != 12 {
+		t.Errorf("int\ngot =%v, %v\nwant=%v", got, err, 12)
+	}
+	if got, err := typedParse[bool](t, ""); err != nil || got {
+		t.Errorf("empty is skipped\ngot =%v, %v\nwant=%v", got, err, false)
+	}
+	if got, err := typedParse[[]int](t, "1;2", WithSeparator(";")); err != nil || !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("slice\ngot =%v, %v\nwant=%v", got, err, []int{1, 2})
+	}
+	if got, err := typedParse[map[string]string](t, "a=1,b=2"); err != nil || !reflect.DeepEqual(got, map[string]string{"a": "1", "b": "2"}) {
+		t.Errorf("map\ngot =%v, %v", got, err)
+	}
+	if got, err := typedParse[typedLevel](t, "info"); err != nil || got != 1 {
+		t.Errorf("setter\ngot =%v, %v\nwant=%v", got, err, 1)
+	}
+	if _, err := typedParse[int](t, "abc"); err == nil {
+		t.Errorf("expect parse error")
+	}
+
+	p, v, err := NewTyped[string]("name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.IsConcurrentSafe || p.Type != reflect.TypeOf("") {
+		t.Errorf("param\ngot =%+v", p)
+	}
+	_ = p.Parse("first")
+	_ = p.Parse("second")
+	if v.Get() != "second" {
+		t.Errorf("latest value\ngot =%q\nwant=%q", v.Get(), "second")
+	}
+}
+
+func TestRegisterParser(t *testing.T) {
+	if _, _, err := NewTyped[*url.URL]("name"); err == nil {
+		t.Errorf("expect error without parser")
+	}
+	RegisterParser(func(s string) (*url.URL, error) { return url.Parse(s) })
+	got, err := typedParse[*url.URL](t, "https://example.com/a")
+	if err != nil || got.Host != "example.com" {
+		t.Errorf("registered parser\ngot =%v, %v\nwant=%v", got, err, "example.com")
+	}
+
+	//Replaces the default conversion
+	RegisterParser(func(s string) (typedLevel, error) { return typedLevel(len(s)), nil })
+	defer RegisterParser(func(s string) (typedLevel, error) {
+		var l typedLevel
+		return l, l.Set(s)
+	})
+	if got, err := typedParse[typedLevel](t, strings.Repeat("x", 5)); err != nil || got != 5 {
+		t.Errorf("replaced parser\ngot =%v, %v\nwant=%v", got, err, 5)
+	}
+}
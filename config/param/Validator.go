@@ -0,0 +1,266 @@
+package param
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+)
+
+// Validator checks the raw value, before Parse. See WithValidator().
+type Validator struct {
+	//Desc for the usage, like `min:1`. Empty for a custom validator.
+	Desc     string
+	Validate func(s string) error
+}
+
+// WithValidator checks the raw value before Parse, at Init and on every Loader refresh.
+// A value failing the check never reaches Parse. Can be repeated.
+//
+// The empty value is not checked, see WithIsMandatory().
+func WithValidator(f func(s string) error) paramOption {
+	return func(p *Param) error {
+		if f == nil {
+			return fmt.Errorf("validator can't be nil")
+		}
+		p.Validators = append(p.Validators, Validator{Validate: f})
+		return nil
+	}
+}
+
+// WithMin checks the value is >= min. A number like `1` or a duration like `1s`.
+//
+// For a slice or a map, each value is checked.
+func WithMin(min string) paramOption {
+	return withLimit("min", min, func(c int) bool { return c >= 0 })
+}
+
+// WithMax checks the value is <= max. A number like `100` or a duration like `1m`.
+//
+// For a slice or a map, each value is checked.
+func WithMax(max string) paramOption {
+	return withLimit("max", max, func(c int) bool { return c <= 0 })
+}
+
+// WithLength checks the number of characters is between min and max. A negative max means no max.
+//
+// For a slice or a map, each value is checked.
+func WithLength(min, max int) paramOption {
+	return func(p *Param) error {
+		if min < 0 || max >= 0 && max < min {
+			return fmt.Errorf("invalid length, min:%d max:%d", min, max)
+		}
+		desc := fmt.Sprintf("length:%d..%d", min, max)
+		if max < 0 {
+			desc = fmt.Sprintf("length:%d..", min)
+		}
+		p.Validators = append(p.Validators, Validator{Desc: desc, Validate: func(s string) error {
+			return p.eachItem(s, func(s string) error {
+				n := utf8.RuneCountInString(s)
+				if n < min || max >= 0 && n > max {
+					return fmt.Errorf("got length:%d for value:%q, expect %s", n, s, desc)
+				}
+				return nil
+			})
+		}})
+		return nil
+	}
+}
+
+// WithPattern checks the value matches the regular expression, like `^[a-z]+$`.
+//
+// For a slice or a map, each value is checked.
+func WithPattern(expr string) paramOption {
+	return func(p *Param) error {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("invalid pattern, %w", err)
+		}
+		p.Validators = append(p.Validators, Validator{Desc: "pattern:" + expr, Validate: func(s string) error {
+			return p.eachItem(s, func(s string) error {
+				if !re.MatchString(s) {
+					return fmt.Errorf("got value:%q, expect pattern:%q", s, expr)
+				}
+				return nil
+			})
+		}})
+		return nil
+	}
+}
+
+// WithURL checks the value is an absolute URL, with a scheme and a host. Like `https://example.com/path`.
+func WithURL() paramOption {
+	return func(p *Param) error {
+		p.Validators = append(p.Validators, Validator{Desc: "url", Validate: func(s string) error {
+			return p.eachItem(s, func(s string) error {
+				u, err := url.Parse(s)
+				if err != nil {
+					return err
+				}
+				if u.Scheme == "" || u.Host == "" {
+					return fmt.Errorf("got value:%q, expect an absolute URL", s)
+				}
+				return nil
+			})
+		}})
+		return nil
+	}
+}
+
+// WithHostPort checks the value is `host:port`, like `localhost:8080` or `:8080`.
+func WithHostPort() paramOption {
+	return func(p *Param) error {
+		p.Validators = append(p.Validators, Validator{Desc: "host:port", Validate: func(s string) error {
+			return p.eachItem(s, func(s string) error {
+				_, port, err := net.SplitHostPort(s)
+				if err != nil {
+					return err
+				}
+				if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+					return fmt.Errorf("got value:%q, expect a port between 1 and 65535", s)
+				}
+				return nil
+			})
+		}})
+		return nil
+	}
+}
+
+// WithFileExists checks the value is the path of an existing file. Not a directory.
+func WithFileExists() paramOption {
+	return func(p *Param) error {
+		p.Validators = append(p.Validators, Validator{Desc: "file exists", Validate: func(s string) error {
+			return p.eachItem(s, func(s string) error {
+				info, err := os.Stat(s)
+				if err != nil {
+					return err
+				}
+				if info.IsDir() {
+					return fmt.Errorf("got path:%q, expect a file, not a directory", s)
+				}
+				return nil
+			})
+		}})
+		return nil
+	}
+}
+
+// Validate runs all the validators on the raw value. The empty value is not checked.
+func (p Param) Validate(s string) error {
+	if s == "" {
+		return nil
+	}
+	for _, v := range p.Validators {
+		if err := v.Validate(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withLimit compares each value with the limit, a number or a duration.
+func withLimit(name string, limit string, ok func(compare int) bool) paramOption {
+	return func(p *Param) error {
+		l, err := parseLimit(limit)
+		if err != nil {
+			return fmt.Errorf("invalid %s:%q, expect a number or a duration", name, limit)
+		}
+		desc := name + ":" + limit
+		p.Validators = append(p.Validators, Validator{Desc: desc, Validate: func(s string) error {
+			return p.eachItem(s, func(s string) error {
+				c, err := l.compare(s)
+				if err != nil {
+					return err
+				}
+				if !ok(c) {
+					return fmt.Errorf("got value:%q, expect %s", s, desc)
+				}
+				return nil
+			})
+		}})
+		return nil
+	}
+}
+
+// limit is a number or a duration, see WithMin().
+type limit struct {
+	number     float64
+	duration   time.Duration
+	isDuration bool
+}
+
+func parseLimit(s string) (limit, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return limit{number: f}, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return limit{}, err
+	}
+	return limit{duration: d, isDuration: true}, nil
+}
+
+// compare the value with the limit: -1 when smaller, 0 when equal, +1 when bigger.
+func (l limit) compare(s string) (int, error) {
+	var diff float64
+	if l.isDuration {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("got value:%q, expect a duration", s)
+		}
+		diff = float64(d - l.duration)
+	} else {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("got value:%q, expect a number", s)
+		}
+		diff = f - l.number
+	}
+	switch {
+	case diff < 0:
+		return -1, nil
+	case diff > 0:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// eachItem checks each value of a slice or a map, or the value otherwise.
+//
+// The error of a sensitive param doesn't show the item, only its position.
+func (p *Param) eachItem(s string, f func(s string) error) error {
+	sep := p.ListSeparator()
+	if sep == "" {
+		return f(s)
+	}
+	isMap := p.IsMap()
+	for i, item := range SplitList(s, sep) {
+		if isMap {
+			_, v, err := SplitKeyValue(item)
+			if err != nil {
+				return p.redactItem(err, i, item)
+			}
+			item = v
+		}
+		if err := f(item); err != nil {
+			return p.redactItem(err, i, item)
+		}
+	}
+	return nil
+}
+
+// redactItem hides the item in the error when the param is sensitive.
+func (p *Param) redactItem(err error, i int, item string) error {
+	if !p.IsSensitive {
+		return err
+	}
+	return errors.SensitiveError{Err: fmt.Errorf("item:%d, %w", i, err), Redact: func(s string) string {
+		return errors.RedactValues(s, item)
+	}}
+}
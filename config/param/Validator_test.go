@@ -0,0 +1,84 @@
+package param
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	custom := WithValidator(func(s string) error {
+		if s == "forbidden" {
+			return os.ErrPermission
+		}
+		return nil
+	})
+	tests := []struct {
+		name    string
+		opts    []paramOption
+		value   string
+		wantErr bool
+	}{
+		{name: "empty not checked", opts: []paramOption{WithMin("1")}, value: ""},
+		{name: "custom ok", opts: []paramOption{custom}, value: "ok"},
+		{name: "custom ko", opts: []paramOption{custom}, value: "forbidden", wantErr: true},
+		{name: "min ok", opts: []paramOption{WithMin("1")}, value: "1"},
+		{name: "min ko", opts: []paramOption{WithMin("1")}, value: "0.5", wantErr: true},
+		{name: "max ok", opts: []paramOption{WithMax("100")}, value: "100"},
+		{name: "max ko", opts: []paramOption{WithMax("100")}, value: "101", wantErr: true},
+		{name: "not a number", opts: []paramOption{WithMax("100")}, value: "abc", wantErr: true},
+		{name: "duration ok", opts: []paramOption{WithMin("1s"), WithMax("1m")}, value: "30s"},
+		{name: "duration ko", opts: []paramOption{WithMin("1s"), WithMax("1m")}, value: "2m", wantErr: true},
+		{name: "length ok", opts: []paramOption{WithLength(2, 3)}, value: "été"},
+		{name: "length ko", opts: []paramOption{WithLength(2, 3)}, value: "four", wantErr: true},
+		{name: "length no max", opts: []paramOption{WithLength(2, -1)}, value: "a long value"},
+		{name: "pattern ok", opts: []paramOption{WithPattern("^[a-z]+$")}, value: "abc"},
+		{name: "pattern ko", opts: []paramOption{WithPattern("^[a-z]+$")}, value: "ABC", wantErr: true},
+		{name: "url ok", opts: []paramOption{WithURL()}, value: "https://example.com/a"},
+		{name: "url ko", opts: []paramOption{WithURL()}, value: "example.com", wantErr: true},
+		{name: "host port ok", opts: []paramOption{WithHostPort()}, value: "localhost:8080"},
+		{name: "host port no host", opts: []paramOption{WithHostPort()}, value: ":8080"},
+		{name: "host port ko", opts: []paramOption{WithHostPort()}, value: "localhost:99999", wantErr: true},
+		{name: "file ok", opts: []paramOption{WithFileExists()}, value: file},
+		{name: "file missing", opts: []paramOption{WithFileExists()}, value: filepath.Join(dir, "missing"), wantErr: true},
+		{name: "file is dir", opts: []paramOption{WithFileExists()}, value: dir, wantErr: true},
+		{name: "slice each value", opts: []paramOption{WithType(typeOf[[]int]()), WithMax("10")}, value: "1,20", wantErr: true},
+		{name: "map each value", opts: []paramOption{WithType(typeOf[map[string]int]()), WithMax("10")}, value: "a=1,b=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New("name", func(s string) error { return nil }, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p.Validate(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidators_invalid(t *testing.T) {
+	for _, opt := range []paramOption{WithMin("abc"), WithMax(""), WithLength(3, 2), WithPattern("("), WithValidator(nil)} {
+		if _, err := New("name", func(s string) error { return nil }, opt); err == nil {
+			t.Errorf("expect error for an invalid validator")
+		}
+	}
+}
+
+func TestValidators_sensitiveItem(t *testing.T) {
+	p, err := New("name", func(s string) error { return nil }, WithType(typeOf[[]string]()), WithPattern("^[a-z]+$"), WithSensitive())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Validate("abc,Secret-1")
+	want := `item:1, got value:"[redacted]", expect pattern:"^[a-z]+$"`
+	if err == nil || err.Error() != want {
+		t.Errorf("Validate() sensitive item\ngot =%v\nwant=%q", err, want)
+	}
+}
@@ -0,0 +1,80 @@
+package source
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// Source is where a param value comes from.
+	Source string
+)
+
+const (
+	// None when no source provided a value.
+	None       Source = ""
+	Default    Source = "Default"
+	ConfigFile Source = "ConfigFile"
+	Loader     Source = "Loader"
+	DotEnv     Source = "DotEnv"
+	EnvVar     Source = "EnvVar"
+	Flag       Source = "Flag"
+)
+
+func (s Source) String() string {
+	return string(s)
+}
+
+// priorityDefault is the reading order, highest priority first. See PriorityDefault().
+var priorityDefault = []Source{Flag, EnvVar, DotEnv, Loader, ConfigFile, Default}
+
+// PriorityDefault is the reading order, highest priority first. A new copy on every call.
+func PriorityDefault() []Source {
+	return append([]Source(nil), priorityDefault...)
+}
+
+// CheckPriority validates a reading order: not empty, known sources, no duplicate.
+func CheckPriority(priority []Source) error {
+	if len(priority) == 0 {
+		return fmt.Errorf("empty source priority")
+	}
+	seen := map[Source]bool{}
+	for _, s := range priority {
+		if !s.isKnown() {
+			return fmt.Errorf("unknown source:%q", s)
+		}
+		if seen[s] {
+			return fmt.Errorf("source:%q defined 2 times in priority", s)
+		}
+		seen[s] = true
+	}
+	return nil
+}
+
+// Contains is true when the source is in the priority list.
+func Contains(priority []Source, s Source) bool {
+	for _, p := range priority {
+		if p == s {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatPriority shows the priority list, highest priority first. Like `Flag > EnvVar > Loader > Default`
+func FormatPriority(priority []Source) string {
+	res := make([]string, 0, len(priority))
+	for _, s := range priority {
+		res = append(res, s.String())
+	}
+	return strings.Join(res, " > ")
+}
+
+func (s Source) isKnown() bool {
+	for _, known := range priorityDefault {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
@@ -2,15 +2,17 @@ package config
 
 import (
 	"context"
-	"flag"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
+	"time"
 
 	"log/slog"
 
 	"github.com/vincentkerdraon/configo/config/errors"
 	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/source"
 	"github.com/vincentkerdraon/configo/config/subcommand"
 	"github.com/vincentkerdraon/configo/lock"
 )
@@ -25,196 +27,381 @@ type paramImpl struct {
 	// internal
 	hasValue bool
 
-	// hasEnvVarOrFlag to start the sync or not (depending of system override or not)
+	// values are the raw values found, by source. A source without value is not in the map.
 	//
 	// internal
-	hasEnvVarOrFlag bool
+	values map[source.Source]string
+
+	// priority is the reading order, highest priority first.
+	//
+	// internal
+	priority []source.Source
+
+	// loaderFirst when Loader.AlwaysSync and the Loader value changed. It now overrides the other sources.
+	//
+	// internal
+	loaderFirst bool
+
+	// value is the last raw value set. Used to detect changes when loading again.
+	//
+	// internal
+	value string
+
+	// source of the current value.
+	//
+	// internal
+	source source.Source
+
+	// loadedAt is the last time the value was read.
+	//
+	// internal
+	loadedAt time.Time
+
+	// subCommands where this param is declared, starting with level 0.
+	//
+	// internal
+	subCommands []subcommand.SubCommand
+
+	// loadLock prevents the sync and Reload() from loading the same param at the same time.
+	//
+	// internal
+	loadLock lock.Locker
+
+	// flagStyle to show the flag the way it is parsed.
+	//
+	// internal
+	flagStyle FlagStyle
+
+	// naming derives the flag and env var names, see WithEnvVarNaming().
+	//
+	// internal
+	naming naming
 }
 
-func (p *paramImpl) init(ctx context.Context, logger *slog.Logger, lock lock.Locker, subCommands []subcommand.SubCommand) (initFlag func(*flag.FlagSet), setValue func() error, _ error) {
-	var hasEnvVarOrFlag bool
-	val := p.Default
+func (p *paramImpl) init(ctx context.Context, logger *slog.Logger, lock lock.Locker, subCommands []subcommand.SubCommand, in initInputs) (setValue func() error, _ error) {
+	p.subCommands = append([]subcommand.SubCommand{}, subCommands...)
+	p.values = map[source.Source]string{}
+	if p.Default != "" && p.reads(source.Default) {
+		p.values[source.Default] = p.Default
+	}
+	if valConfigFile, ok := p.configFileValue(in); ok {
+		p.values[source.ConfigFile] = valConfigFile
+		logger.DebugContext(ctx, "found in config file", slog.String("Param", p.Name.String()), slog.String("Value", p.redact(valConfigFile)))
+	}
 
-	var valEnvVar string
-	if p.EnvVar.Use {
-		valEnvVar = p.loadEnvVar()
+	if p.EnvVar.Use && p.reads(source.DotEnv) {
+		valDotEnv, err := p.loadDotEnv(in)
+		if err != nil {
+			return nil, errors.ParamConfigError{ParamName: p.Name, SubCommands: subCommands, Err: err}
+		}
+		if valDotEnv != "" {
+			p.values[source.DotEnv] = valDotEnv
+			logger.DebugContext(ctx, "found in .env file", slog.String("Param", p.Name.String()), slog.String("Value", p.redact(valDotEnv)))
+		}
+	}
+	if p.EnvVar.Use && p.reads(source.EnvVar) {
+		valEnvVar, err := p.loadEnvVar()
+		if err != nil {
+			return nil, errors.ParamConfigError{ParamName: p.Name, SubCommands: subCommands, Err: err}
+		}
 		if valEnvVar != "" {
-			hasEnvVarOrFlag = true
-			val = valEnvVar
-			logger.DebugContext(ctx, "found env var", slog.String("Param", p.Name.String()), slog.String("Value", val))
+			p.values[source.EnvVar] = valEnvVar
+			logger.DebugContext(ctx, "found env var", slog.String("Param", p.Name.String()), slog.String("Value", p.redact(valEnvVar)))
 		} else {
 			logger.DebugContext(ctx, "no env var found", slog.String("Param", p.Name.String()))
 		}
 	}
-	tmpFlagVal := val
-	if p.Flag.Use {
-		//using tmpFlagVal to detect if the value was set or not (even when same as previous step)
-		initFlag = p.loadFlag(logger, &tmpFlagVal)
+	fv := in.flags[flagKey{depth: len(subCommands) - 1, name: p.Name}]
+	if fv == nil {
+		fv = &flagValue{}
+	}
+	if fv.isSet {
+		logger.DebugContext(ctx, "found flag", slog.String("Param", p.Name.String()), slog.String("Value", p.redact(fv.value)))
 	}
 	setValue = func() error {
-		if tmpFlagVal != val {
-			hasEnvVarOrFlag = true
-			val = tmpFlagVal
+		if fv.isSet {
+			p.values[source.Flag] = fv.value
 		}
 
-		if !hasEnvVarOrFlag {
+		if p.readsLoader() {
 			if p.Loader.Getter != nil {
 				valLoader, err := p.Loader.Getter(ctx)
 				if err != nil {
-					return errors.ParamConfigError{ParamName: p.Name, SubCommands: subCommands, Err: errors.ConfigLoaderFetchError{Err: err}}
+					return errors.ParamConfigError{ParamName: p.Name, SubCommands: subCommands, Err: errors.ConfigLoaderFetchError{Err: p.redactErr(err)}}
 				}
 				if valLoader != "" {
-					val = valLoader
-					logger.DebugContext(ctx, "Loader returns value", slog.String("Param", p.Name.String()), slog.String("Value", val))
+					p.values[source.Loader] = valLoader
+					logger.DebugContext(ctx, "Loader returns value", slog.String("Param", p.Name.String()), slog.String("Value", p.redact(valLoader)))
 				} else {
 					logger.DebugContext(ctx, "Loader returns no value", slog.String("Param", p.Name.String()))
 				}
 			}
 		} else {
-			logger.DebugContext(ctx, "skipping Loader, found env var or flag", slog.String("Param", p.Name.String()), slog.String("Value", val))
+			logger.DebugContext(ctx, "skipping Loader, found a higher priority source", slog.String("Param", p.Name.String()))
 		}
 
-		//Check mandatory
-		if p.IsMandatory && val == "" {
-			return errors.ParamConfigError{ParamName: p.Name, SubCommands: subCommands, Err: errors.ErrMandatoryValue}
-		}
+		p.loadedAt = time.Now()
+		//Always parse at init, even when empty.
+		_, err := p.apply(ctx, lock, subCommands, true)
+		return err
+	}
 
-		//check enum
-		if err := p.checkEnum(val); err != nil {
-			return errors.ParamConfigError{ParamName: p.Name, SubCommands: subCommands, Err: err}
-		}
+	return setValue, nil
+}
 
-		//Check if flag or envvar set. In this case, don't start sync.
-		p.hasEnvVarOrFlag = hasEnvVarOrFlag
-		//Check exclusive values
-		p.hasValue = (val != "")
+// reads is true when the source is in the priority list.
+func (p *paramImpl) reads(src source.Source) bool {
+	return source.Contains(p.sourcePriority(), src)
+}
 
-		return p.lockAndParse(ctx, lock, val, subCommands)
+// readsLoader is true when the Loader must be used: no higher priority source has a value (or using Loader.AlwaysSync).
+func (p *paramImpl) readsLoader() bool {
+	if !p.reads(source.Loader) {
+		return false
+	}
+	if p.Loader.AlwaysSync {
+		return true
 	}
+	for _, src := range p.sourcePriority() {
+		if src == source.Loader {
+			return true
+		}
+		if _, ok := p.values[src]; ok {
+			return false
+		}
+	}
+	return true
+}
 
-	return initFlag, setValue, nil
+func (p *paramImpl) sourcePriority() []source.Source {
+	priority := p.priority
+	if priority == nil {
+		priority = p.SourcePriority
+	}
+	if priority == nil {
+		priority = source.PriorityDefault()
+	}
+	if !p.loaderFirst {
+		return priority
+	}
+	res := []source.Source{source.Loader}
+	for _, src := range priority {
+		if src != source.Loader {
+			res = append(res, src)
+		}
+	}
+	return res
 }
 
-func (p paramImpl) checkEnum(val string) error {
-	if len(p.EnumValues) == 0 {
-		return nil
+// pick finds the value with the highest priority.
+func (p *paramImpl) pick() (source.Source, string) {
+	for _, src := range p.sourcePriority() {
+		if val, ok := p.values[src]; ok {
+			return src, val
+		}
 	}
-	for _, v := range p.EnumValues {
-		if v == val {
-			return nil
+	return source.None, ""
+}
+
+// overridden are the sources with a value, but losing against the current source. Highest priority first.
+func (p *paramImpl) overridden() []SourceValue {
+	res := []SourceValue{}
+	for _, src := range p.sourcePriority() {
+		if val, ok := p.values[src]; ok && src != p.source {
+			res = append(res, SourceValue{Source: src, Value: val})
 		}
 	}
-	return fmt.Errorf("got value:%q, expect one of:%v", val, p.EnumValues)
+	return res
 }
 
-func (p paramImpl) usage(indent int) string {
-	indentString := strings.Repeat("\t", indent)
-	res := indentString + "Param: " + p.Name.String()
-	append := func(s string) {
-		res += "\n" + indentString + "\t" + s
+// apply checks and parses the value with the highest priority.
+//
+// Nothing is done when the value did not change, unless force.
+func (p *paramImpl) apply(ctx context.Context, lock lock.Locker, subCommands []subcommand.SubCommand, force bool) (changed bool, _ error) {
+	src, val := p.pick()
+	if !force && val == p.value {
+		p.source = src
+		return false, nil
 	}
-	if len(p.Desc) > 0 {
-		append("Description: " + p.Desc)
+
+	//Check mandatory
+	if p.IsMandatory && val == "" {
+		return false, errors.ParamConfigError{ParamName: p.Name, SubCommands: subCommands, Err: errors.ErrMandatoryValue}
 	}
-	if len(p.Examples) > 0 {
-		append(fmt.Sprintf("Example: %v", p.Examples))
+
+	//check enum. Parse receives the declared value, see param.WithEnumCaseInsensitive().
+	val, err := p.checkEnum(val)
+	if err != nil {
+		return false, errors.ParamConfigError{ParamName: p.Name, SubCommands: subCommands, Err: p.redactErr(err, val)}
 	}
-	if p.Default != "" {
-		append("Default: " + p.Default)
+
+	//Validate before Parse, a bad value never reaches the destination.
+	if err := p.Validate(val); err != nil {
+		return false, errors.ParamConfigError{ParamName: p.Name, SubCommands: subCommands, Err: errors.ParamValidationError{Err: p.redactErr(err, val)}}
 	}
-	if len(p.EnumValues) > 0 {
-		append(fmt.Sprintf("EnumValues: %v", p.EnumValues))
+
+	if err := p.lockAndParse(ctx, lock, val, subCommands); err != nil {
+		return false, err
 	}
-	if p.IsMandatory {
-		append("Mandatory value.")
+	changed = val != p.value
+	//Check exclusive values
+	p.hasValue = (val != "")
+	p.value = val
+	p.source = src
+	return changed, nil
+}
+
+func (p paramImpl) checkEnum(val string) (string, error) {
+	v, found := p.EnumValue(val)
+	if !found {
+		return val, fmt.Errorf("got value:%q, expect one of:%v", val, p.EnumValues)
 	}
-	if p.IsSubCommandLocal {
-		append("This param won't be available in sub commands.")
+	return v, nil
+}
+
+func (p paramImpl) usage(indent int) string {
+	return renderTextParam(p.usageModel(), indent, textStylePlain)
+}
+
+// envVarName is the explicit name, or derived from the param name. Always with the prefix, see WithEnvPrefix().
+func (p paramImpl) envVarName() string {
+	if p.EnvVar.Name != "" {
+		return p.naming.envVarPrefix + p.EnvVar.Name
 	}
-	if p.Flag.Use {
-		if p.Flag.Name == "" {
-			append("Command line flag: -" + p.Name.String())
-		} else {
-			append("Command line flag: -" + p.Flag.Name)
-		}
-	} else {
-		append("Command line flag disable.")
+	if p.naming.envVar != nil {
+		return p.naming.envVarPrefix + p.naming.envVar(p.Name.String())
 	}
-	if p.EnvVar.Use {
-		if p.EnvVar.Name == "" {
-			append("Environment variable name: " + p.Name.String())
-		} else {
-			append("Environment variable name: " + p.Flag.Name)
-		}
-	} else {
-		append("Environment variable disable.")
+	return p.naming.envVarPrefix + p.Name.String()
+}
+
+// loadEnvVar reads the env var, or the file named by the env var `NAME_FILE`.
+func (p paramImpl) loadEnvVar() (string, error) {
+	return lookupEnvVar(p.envVarName(), os.Getenv)
+}
+
+// envVarFileSuffix is the Docker/Kubernetes convention: `FOO_FILE=/run/secrets/foo` to read the value of FOO in this file.
+const envVarFileSuffix = "_FILE"
+
+func lookupEnvVar(name string, getenv func(string) string) (string, error) {
+	if val := getenv(name); val != "" {
+		return val, nil
 	}
-	if p.Loader.Getter != nil {
-		if p.Loader.SynchroFrequency == 0 {
-			append("Using a custom loader without periodic update.")
-		} else {
-			append("Using a custom loader, refresh every " + p.Loader.SynchroFrequency.String())
-		}
-	} else {
-		append("No custom loader defined.")
+	path := getenv(name + envVarFileSuffix)
+	if path == "" {
+		return "", nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("fail read file from env var:%q, %w", name+envVarFileSuffix, err)
 	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
 
-	return res + "\n"
+// flagValue implements flag.Value. It knows if the flag was set, even when set with the same value.
+type flagValue struct {
+	value string
+	isSet bool
+	//isBool for a switch, `-verbose` alone means `-verbose=true`.
+	isBool bool
+	//sep for a slice or map param. A repeated flag appends, joined with sep.
+	sep string
 }
 
-func (p paramImpl) loadEnvVar() string {
-	var nameEnvVar string
-	if p.EnvVar.Name != "" {
-		nameEnvVar = p.EnvVar.Name
-	} else {
-		nameEnvVar = p.Name.String()
+// IsBoolFlag is used by the std flag package, to accept a flag without value.
+func (f *flagValue) IsBoolFlag() bool {
+	return f.isBool
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
 	}
-	return os.Getenv(nameEnvVar)
+	return f.value
 }
 
-func (p paramImpl) loadFlag(logger *slog.Logger, val *string) func(*flag.FlagSet) {
-	var nameFlag string
+func (f *flagValue) Set(s string) error {
+	if f.isSet && f.sep != "" {
+		//Repeated flag for a slice or map param
+		s = f.value + f.sep + s
+	}
+	f.value = s
+	f.isSet = true
+	return nil
+}
+
+// isBool when the param type is a bool. The flag is then a switch.
+func (p paramImpl) isBool() bool {
+	t := p.Type
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t != nil && t.Kind() == reflect.Bool
+}
+
+// flagName is the explicit name, or derived from the param name. See WithFlagNaming().
+func (p paramImpl) flagName() string {
 	if p.Flag.Name != "" {
-		nameFlag = p.Flag.Name
-	} else {
-		nameFlag = p.Name.String()
+		return p.Flag.Name
+	}
+	if p.naming.flag != nil {
+		return p.naming.flag(p.Name.String())
 	}
+	return p.Name.String()
+}
 
-	return func(fs *flag.FlagSet) {
-		logger.Debug("checking flag", slog.String("Param", p.Name.String()), slog.String("nameFlag", nameFlag))
-		fs.StringVar(val, nameFlag, *val, p.usage(0))
+// flagForms are the ways to write the flag, long name first. Like `--verbose, -v`
+func (p paramImpl) flagForms() []string {
+	dashes := "-"
+	if p.flagStyle == FlagStyleGNU {
+		dashes = "--"
+	}
+	res := []string{dashes + p.flagName()}
+	if p.Flag.ShortName != 0 {
+		res = append(res, "-"+string(p.Flag.ShortName))
 	}
+	return res
 }
 
-func (p paramImpl) load(ctx context.Context, lock lock.Locker, valuePrevious string, subCommands []subcommand.SubCommand) (valueNew string, _ error) {
+// load fetches the value with the Loader and applies it.
+func (p *paramImpl) load(ctx context.Context, lock lock.Locker, subCommands []subcommand.SubCommand) (changed bool, _ error) {
 	if p.Loader.Getter == nil {
-		return "", nil
+		return false, nil
 	}
 
 	val, err := p.Loader.Getter(ctx)
 	if err != nil {
-		return "", errors.ConfigLoaderError{Err: errors.ConfigLoaderFetchError{Err: err}}
+		return false, errors.ConfigLoaderError{Err: errors.ConfigLoaderFetchError{Err: p.redactErr(err)}}
 	}
-	if valuePrevious == val {
-		return valuePrevious, nil
+	p.loadedAt = time.Now()
+	if valPrevious, ok := p.values[source.Loader]; p.Loader.AlwaysSync && ok && val != valPrevious {
+		p.loaderFirst = true
 	}
-	if err := p.lockAndParse(ctx, lock, val, subCommands); err != nil {
-		return "", errors.ConfigLoaderError{Err: err}
+	if val == "" {
+		delete(p.values, source.Loader)
+	} else {
+		p.values[source.Loader] = val
+	}
+	changed, err = p.apply(ctx, lock, subCommands, false)
+	if err != nil {
+		return false, errors.ConfigLoaderError{Err: err}
 	}
-	return val, nil
+	return changed, nil
 }
 
 func (p *paramImpl) lockAndParse(ctx context.Context, lock lock.Locker, s string, subCommands []subcommand.SubCommand) error {
 	//Because the value is set using outside code, we don't know if it is always quick.
 	//Adding a protection where Timeout can be used.
-	if err := lock.LockWithContext(ctx); err != nil {
-		return err
+	//Not needed when the value is swapped atomically, see param.NewTyped().
+	if !p.IsConcurrentSafe {
+		if err := lock.LockWithContext(ctx); err != nil {
+			return err
+		}
+		defer lock.Unlock()
 	}
-	defer lock.Unlock()
 
 	err := p.Parse(s)
 	if err != nil {
-		return errors.ParamConfigError{ParamName: p.Name, SubCommands: subCommands, Err: errors.ParamParseError{Err: err}}
+		return errors.ParamConfigError{ParamName: p.Name, SubCommands: subCommands, Err: errors.ParamParseError{Err: p.redactErr(err, s)}}
 	}
 	return nil
 }
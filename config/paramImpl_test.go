@@ -2,7 +2,16 @@ package config
 
 import (
 	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"testing"
+
+	"github.com/vincentkerdraon/configo/config/errors"
+	"github.com/vincentkerdraon/configo/config/param"
+	"github.com/vincentkerdraon/configo/config/param/source"
+	"github.com/vincentkerdraon/configo/secretrotation"
 )
 
 func Test_param_default_value(t *testing.T) {
@@ -34,3 +43,285 @@ func Test_param_default_value(t *testing.T) {
 		t.Errorf("use init struct value\ngot =%q\nwant=%q", user.Name, expected)
 	}
 }
+
+func Test_param_source_priority(t *testing.T) {
+	t.Setenv("CONFIGO_TEST_PRIORITY", "fromEnv")
+	loaderValue := "fromLoader"
+	loader := func(ctx context.Context) (string, error) { return loaderValue, nil }
+	env := param.WithEnvVar(param.WithEnvVarName("CONFIGO_TEST_PRIORITY"))
+
+	tests := []struct {
+		name          string
+		newParam      func(parse func(string) error) (*param.Param, error)
+		managerOpts   []configOptionsF
+		reload        string
+		wantInit      string
+		wantReload    string
+		wantUsageLine string
+	}{
+		{
+			name: "default priority, env var wins",
+			newParam: func(parse func(string) error) (*param.Param, error) {
+				return param.New("P", parse, env, param.WithLoader(loader))
+			},
+			reload:     "fromLoader2",
+			wantInit:   "fromEnv",
+			wantReload: "fromEnv",
+		},
+		{
+			name: "param priority, loader wins",
+			newParam: func(parse func(string) error) (*param.Param, error) {
+				return param.New("P", parse, env, param.WithLoader(loader), param.WithSourcePriority(source.Flag, source.Loader, source.EnvVar, source.Default))
+			},
+			reload:        "fromLoader2",
+			wantInit:      "fromLoader",
+			wantReload:    "fromLoader2",
+			wantUsageLine: "Source priority: Flag > Loader > EnvVar > Default",
+		},
+		{
+			name: "manager priority, env var not read",
+			newParam: func(parse func(string) error) (*param.Param, error) {
+				return param.New("P", parse, env, param.WithDefault("fromDefault"))
+			},
+			managerOpts: []configOptionsF{WithSourcePriority(source.Flag, source.Default)},
+			wantInit:    "fromDefault",
+			wantReload:  "fromDefault",
+		},
+		{
+			name: "loader always sync",
+			newParam: func(parse func(string) error) (*param.Param, error) {
+				return param.New("P", parse, env, param.WithLoader(loader, param.WithAlwaysSync(true)))
+			},
+			reload:     "fromLoader2",
+			wantInit:   "fromEnv",
+			wantReload: "fromLoader2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaderValue = "fromLoader"
+			var got string
+			p, err := tt.newParam(func(s string) error { got = s; return nil })
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := New(append(tt.managerOpts, WithParams(p))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Init(context.Background(), WithInputArgs([]string{})); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.wantInit {
+				t.Errorf("Init\ngot =%q\nwant=%q", got, tt.wantInit)
+			}
+			if tt.reload != "" {
+				loaderValue = tt.reload
+			}
+			if err := c.Reload(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.wantReload {
+				t.Errorf("Reload\ngot =%q\nwant=%q", got, tt.wantReload)
+			}
+			if tt.wantUsageLine != "" && !strings.Contains(c.Usage(0), tt.wantUsageLine) {
+				t.Errorf("Usage\ngot =%s\nwant line=%q", c.Usage(0), tt.wantUsageLine)
+			}
+		})
+	}
+}
+
+func Test_param_bool_switch(t *testing.T) {
+	t.Setenv("CONFIGO_TEST_SWITCH_DEBUG", "true")
+	conf := struct {
+		Verbose bool
+		Quiet   bool
+		Debug   bool `envVar:"CONFIGO_TEST_SWITCH_DEBUG"`
+		Name    string
+	}{Quiet: true}
+	c, err := New(WithParamsFromStructTag(&conf, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{"-Verbose", "-Quiet=false", "-Name", "n"})); err != nil {
+		t.Fatal(err)
+	}
+	if !conf.Verbose || conf.Quiet || !conf.Debug || conf.Name != "n" {
+		t.Errorf("bool switch\ngot =%+v\nwant={Verbose:true Quiet:false Debug:true Name:n}", conf)
+	}
+
+	var got bool
+	p, err := param.NewBool("v", func(b bool) error { got = b; return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = New(WithParams(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{"-v"})); err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Errorf("NewBool switch\ngot =%v\nwant=%v", got, true)
+	}
+	if want := "Command line flag: -v (switch, same as -v=true)"; !strings.Contains(c.Usage(0), want) {
+		t.Errorf("Usage\ngot =%s\nwant line=%q", c.Usage(0), want)
+	}
+}
+
+func Test_param_slice_map(t *testing.T) {
+	t.Setenv("CONFIGO_TEST_SLICE_LABELS", "env=prod;team=core")
+	conf := struct {
+		Peers  []string
+		Ports  []int
+		Labels map[string]string `envVar:"CONFIGO_TEST_SLICE_LABELS" sep:";"`
+	}{}
+	c, err := New(WithParamsFromStructTag(&conf, ""), WithFlagStyle(FlagStyleGNU))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{"--Peers=a,b", "--Peers", "c", "--Ports=80"})); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(conf.Peers) != "[a b c]" || fmt.Sprint(conf.Ports) != "[80]" || fmt.Sprint(conf.Labels) != "map[env:prod team:core]" {
+		t.Errorf("slice and map\ngot =%+v", conf)
+	}
+	if want := `List of values, separated by ";". A repeated flag appends.`; !strings.Contains(c.Usage(0), want) {
+		t.Errorf("Usage\ngot =%s\nwant line=%q", c.Usage(0), want)
+	}
+}
+
+type paramTestMode string
+
+func (paramTestMode) Values() []string { return []string{"local", "s3"} }
+
+func Test_param_enum_type(t *testing.T) {
+	conf := struct {
+		Mode paramTestMode
+	}{}
+	p, err := param.NewParamFromStructTag(&conf, "Mode", nil, param.WithEnumCaseInsensitive())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithParams(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{"-Mode=S3"})); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Mode != "s3" {
+		t.Errorf("case insensitive, declared value\ngot =%q\nwant=%q", conf.Mode, "s3")
+	}
+	if err := c.Init(context.Background(), WithInputArgs([]string{"-Mode=gcs"})); err == nil {
+		t.Errorf("expect error for a value not in the enum")
+	}
+
+	if got := c.complete([]string{"-Mode", "S"}); fmt.Sprint(got) != "[s3]" {
+		t.Errorf("completion\ngot =%v\nwant=%v", got, "[s3]")
+	}
+	if want := "EnumValues: [local s3] (case insensitive)"; !strings.Contains(c.Usage(0), want) {
+		t.Errorf("usage\ngot =%s\nwant=%q", c.Usage(0), want)
+	}
+	schema, err := c.JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"enum": [`; !strings.Contains(string(schema), want) {
+		t.Errorf("schema\ngot =%s\nwant=%q", schema, want)
+	}
+}
+
+func Test_param_sensitive(t *testing.T) {
+	t.Setenv("CONFIGO_TEST_API_KEY", "s3cr3t-env")
+	conf := struct {
+		APIKey   string `envVar:"CONFIGO_TEST_API_KEY" sensitive:"true" default:"s3cr3t-default"`
+		Password int    `sensitive:"true"`
+		Mode     string `sensitive:"true" enumValues:"a;b"`
+	}{}
+	var logs strings.Builder
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c, err := New(WithParamsFromStructTag(&conf, ""), WithLogger(logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := c.Params["APIKey"]; !p.IsSensitive {
+		t.Errorf("struct tag sensitive\ngot =%+v", p)
+	}
+
+	//One error at a time, the params are read in any order.
+	err = c.Init(context.Background(), WithInputArgs([]string{"-Password=s3cr3t-flag", "-Mode=a"}))
+	if err == nil {
+		t.Fatal("expect parse error")
+	}
+	for _, secret := range []string{"s3cr3t-env", "s3cr3t-flag", "s3cr3t-enum", "s3cr3t-default"} {
+		if strings.Contains(err.Error(), secret) {
+			t.Errorf("error leaks %q\ngot =%s", secret, err)
+		}
+		if strings.Contains(logs.String(), secret) {
+			t.Errorf("logs leak %q\ngot =%s", secret, logs.String())
+		}
+	}
+	if !strings.Contains(err.Error(), secretrotation.SecretRedacted) {
+		t.Errorf("error\ngot =%s\nwant=%q", err, secretrotation.SecretRedacted)
+	}
+	parseErr := errors.ParamParseError{}
+	if !stderrors.As(err, &parseErr) {
+		t.Errorf("expect ParamParseError, got =%#v", err)
+	}
+
+	err = c.Init(context.Background(), WithInputArgs([]string{"-Mode=s3cr3t-enum"}))
+	if err == nil || strings.Contains(err.Error(), "s3cr3t-enum") {
+		t.Errorf("enum error\ngot =%v", err)
+	}
+	if strings.Contains(logs.String(), "s3cr3t-enum") {
+		t.Errorf("logs leak %q\ngot =%s", "s3cr3t-enum", logs.String())
+	}
+
+	if err := c.Init(context.Background(), WithInputArgs([]string{"-Mode=a"})); err != nil {
+		t.Fatal(err)
+	}
+	if conf.APIKey != "s3cr3t-env" {
+		t.Errorf("value\ngot =%q\nwant=%q", conf.APIKey, "s3cr3t-env")
+	}
+	if p, _ := c.Snapshot().Get("APIKey"); p.Value != secretrotation.SecretRedacted {
+		t.Errorf("snapshot\ngot =%q\nwant=%q", p.Value, secretrotation.SecretRedacted)
+	}
+	if usage := c.Usage(0); strings.Contains(usage, "s3cr3t-default") || !strings.Contains(usage, "Default: "+secretrotation.SecretRedacted) {
+		t.Errorf("usage default\ngot =%s", usage)
+	}
+	if schema, _ := c.JSONSchema(); strings.Contains(string(schema), "s3cr3t-default") {
+		t.Errorf("schema default\ngot =%s", schema)
+	}
+}
+
+func Test_param_sensitiveList(t *testing.T) {
+	conf := struct {
+		Tokens []int             `sensitive:"true"`
+		Keys   map[string]string `sensitive:"true" pattern:"^[a-z]+$"`
+	}{}
+	c, err := New(WithParamsFromStructTag(&conf, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		args   []string
+		secret string
+	}{
+		{name: "parse", args: []string{"-Tokens=1234,s3cr3t-item"}, secret: "s3cr3t-item"},
+		{name: "validation", args: []string{"-Keys=a=abc,b=S3CR3T-ITEM"}, secret: "S3CR3T-ITEM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.Init(context.Background(), WithInputArgs(tt.args))
+			if err == nil {
+				t.Fatal("expect error")
+			}
+			if strings.Contains(err.Error(), tt.secret) || !strings.Contains(err.Error(), secretrotation.SecretRedacted) {
+				t.Errorf("error leaks %q\ngot =%s", tt.secret, err)
+			}
+		})
+	}
+}
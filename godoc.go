@@ -7,38 +7,54 @@ Features:
 
   - Read from flags, env files, local files, remote config
   - Easy use of custom types
-  - Declarative style OR/AND struct tags style
-  - SubCommands with persistent or local flags.
+  - Typed params with a lock-free getter, see param.NewTyped() and param.RegisterParser()
+  - Slice and map params (param.NewStringSlice(), param.NewStringMap(), struct fields []T and map[string]T). A repeated flag appends
+  - Declarative style OR/AND struct tags style. Nested structs (`DB.Host` is `-db-host` and `DB_HOST`), embedded structs flattened (ignored with `prefix:"-"`)
+  - Naming strategies for the env vars and flags (`ReadTimeout` is `READ_TIMEOUT` and `-read-timeout`), global env var prefix with subcommand segments (`MYAPP_DEPLOY_REGION`). See config.WithEnvVarNaming(), config.WithFlagNaming(), config.WithEnvPrefix()
+  - SubCommands with persistent or local flags. A parent flag can be written before or after the subcommand name.
+  - SubCommands aliases, hidden or deprecated subcommands
+  - "Did you mean" suggestions for unknown flags and subcommands, warning for unknown env vars (see config.WithEnvVarCheck())
+  - Positional args after the subcommands, with arity and validation, see config.WithArg()
+  - Command handlers with the context, the positional args and the values, see config.WithRun()
+  - Go style flags (`-name`) or GNU style flags (`--name`, `-n`, bundled `-vx`), see config.WithFlagStyle()
+  - Usage in declaration order, with param groups and hidden params. Built-in `-h`, `--help` and `help <subcommand>`, wrapped at the terminal width
+  - Usage as data (config.Manager.UsageModel()) with pluggable renderers: text, colorized terminal, text/template. See config.WithUsageRenderer()
   - No external libraries
-  - Refresh conf
+  - Refresh conf (periodic sync, on demand with Manager.Reload() or on SIGHUP)
   - Low footprint once the init is done
 
 Parameter options:
   - Mandatory values
-  - Enum values (list of allowed values, implement interface.Values() or interface.List())
+  - Enum values (list of allowed values, or a Go type implementing `Values() []string` or `List() []string`, see param.NewEnum()). Optionally case insensitive
   - Custom flag name or envvar name.
-  - Value validation
+  - Value validation before parsing, at init and on every loader refresh: custom, min/max (numbers and durations), length, regex, URL, host:port, file exists. Struct tags `min`, `max`, `pattern`
   - Description
   - Examples
   - Default value
   - Exclusive params (either param1 or param2 but not both)
+  - Sensitive values (param.WithSensitive() or `sensitive:"true"`), redacted in the logs, the errors, the usage and the snapshots
+  - Rules between params: requires, required if, at least one of, exactly one of, custom check of the snapshot. All the violations are returned together
 
 Limitations:
 
   - Every input is always a string and must be transformed. Empty strings are skipped.
-  - The reading priority is always the same.
 
 Priorities:
 
  1. Default in the code
- 2. Loader (user defined function, read for local file, secret manager...)
- 3. Env Var
- 4. Command line flags
+ 2. Config file (JSON, INI or a registered format, see config.WithConfigFile())
+ 3. Loader (user defined function, read for local file, secret manager...)
+ 4. .env files (see config.WithDotEnvFiles())
+ 5. Env Var (or `NAME_FILE` to read the value in a file)
+ 6. Command line flags
 
 The value will be set in this order, each step overriding the previous.\
 For example a param has a synchronization configuration AND an env var value.
 Then the loader won't be used at all, the env var value will be kept.
 
+This order can be changed with config.WithSourcePriority() or param.WithSourcePriority(). Sources not listed are not read.
+With param.WithAlwaysSync(), the Loader keeps synchronizing even when a higher priority source gave the startup value.
+
 Helpers:
 
   - SecretRotation to help with rotating secrets, for example a consumer calling a service requiring an API secret.
@@ -58,5 +74,6 @@ Real life example of use case: input configuration for a server web. It requires
     - Some secrets are sync regularly and use the secretrotation package.
 
 Because there is a synchronization, use the lock when reading values to avoid race condition with the loader.
+Or use param.NewTyped(): the value is read with Get(), lock-free, and swapped atomically by the loader.
 */
 package configo